@@ -24,6 +24,10 @@ func ParseTerragruntOptions(cliContext *cli.Context) (*options.TerragruntOptions
 	if err != nil {
 		return nil, err
 	}
+	if terragruntOptions.LogFormat == options.LogFormatJSON {
+		terragruntOptions.EnableJSONLogs(cliContext.App.Writer, cliContext.App.ErrWriter)
+		return terragruntOptions, nil
+	}
 	terragruntOptions.Writer = terragruntOptions.Logger.SetStdout(cliContext.App.Writer)
 	terragruntOptions.ErrWriter = terragruntOptions.Logger.SetOut(cliContext.App.ErrWriter)
 	return terragruntOptions, nil
@@ -87,16 +91,35 @@ func parseTerragruntOptionsFromArgs(args []string) (*options.TerragruntOptions,
 	opts.SourceUpdate = parseBooleanArg(args, optTerragruntSourceUpdate, options.EnvSourceUpdate, false)
 	opts.IgnoreDependencyErrors = parseBooleanArg(args, optTerragruntIgnoreDependencyErrors, "", false)
 	opts.AwsProfile = parse(optAWSProfile)
-	opts.ApprovalHandler = parse(optApprovalHandler)
+	opts.ApprovalHandler = parse(optApprovalHandler, os.Getenv(options.EnvApprovalHandler))
 	opts.ApplyTemplate = parseBooleanArg(args, optApplyTemplate, options.EnvApplyTemplate, false)
 	opts.TemplateAdditionalPatterns = parseList(optTemplatePatterns, os.Getenv(options.EnvTemplatePatterns))
 	opts.BootConfigurationPaths = parseList(optBootConfigs, os.Getenv(options.EnvBootConfigs))
 	opts.PreBootConfigurationPaths = parseList(optPreBootConfigs, os.Getenv(options.EnvPreBootConfigs))
 	opts.CheckSourceFolders = !parseBooleanArg(args, optIncludeEmptyFolders, options.EnvIncludeEmptyFolders, false)
 	opts.PluginsDirectory = parse(optPluginsDirectory, os.Getenv(options.EnvPluginsDirectory), "")
+	opts.ReportFile = parse(optReportFile, os.Getenv(options.EnvReportFile))
+	opts.SummaryFormat = parse(optSummaryFormat, os.Getenv(options.EnvSummaryFormat), options.SummarySimple)
+	opts.PlanOutputDir = parse(optPlanOutputDir, os.Getenv(options.EnvPlanOutputDir))
+	opts.PlanInputDir = parse(optPlanInputDir, os.Getenv(options.EnvPlanInputDir))
+	opts.IncludeDirs = parseList(optIncludeDirs, os.Getenv(options.EnvIncludeDirs))
+	opts.ExcludeDirs = parseList(optExcludeDirs, os.Getenv(options.EnvExcludeDirs))
+	opts.OnlyDependenciesOf = parse(optOnlyDependenciesOf, os.Getenv(options.EnvOnlyDependenciesOf))
+	opts.ChangedSince = parse(optChangedSince, os.Getenv(options.EnvChangedSince))
+	opts.FailFast = parseBooleanArg(args, optFailFast, options.EnvFailFast, false)
+	opts.Progress = parseBooleanArg(args, optProgress, options.EnvProgress, false)
+	opts.AllowDestroyProtected = parseBooleanArg(args, optAllowDestroyProtected, options.EnvAllowDestroyProtected, false)
+	opts.ApproveEach = parseBooleanArg(args, optApproveEach, options.EnvApproveEach, false)
+	opts.ResumeRunID = parse(optResume, os.Getenv(options.EnvResume))
+	opts.LogFormat = parse(optLogFormat, os.Getenv(options.EnvLogFormat), options.LogFormatText)
+	opts.TraceFile = parse(optTraceFile, os.Getenv(options.EnvTraceFile))
+	opts.EventWebhook = parse(optEventWebhook, os.Getenv(options.EnvEventWebhook))
 
 	flushDelay := parse(optFlushDelay, os.Getenv(options.EnvFlushDelay), "60s")
 	nbWorkers := parse(optNbWorkers, os.Getenv(options.EnvWorkers), "10")
+	maxFailures := parse(optMaxFailures, os.Getenv(options.EnvMaxFailures), "0")
+	moduleTimeout := parse(optModuleTimeout, os.Getenv(options.EnvModuleTimeout), "0")
+	approvalTimeout := parse(optApprovalTimeout, os.Getenv(options.EnvApprovalTimeout), "0")
 	loggingLevel := parse(optLoggingLevel, os.Getenv(options.EnvLoggingLevel), logrus.InfoLevel.String())
 	fileLoggingDir := parse(optLoggingFileDir, os.Getenv(options.EnvLoggingFileDir))
 	fileLoggingLevel := parse(optLoggingFileLevel, os.Getenv(options.EnvLoggingFileLevel), logrus.DebugLevel.String())
@@ -113,6 +136,26 @@ func parseTerragruntOptionsFromArgs(args []string) (*options.TerragruntOptions,
 		return nil, fmt.Errorf("number of workers must be expressed as integer")
 	}
 
+	if opts.MaxFailures, err = strconv.Atoi(maxFailures); err != nil {
+		return nil, fmt.Errorf("maximum number of failures must be expressed as integer")
+	}
+
+	if opts.ModuleTimeout, err = time.ParseDuration(moduleTimeout); err != nil || opts.ModuleTimeout < 0 {
+		return nil, fmt.Errorf("module timeout must be expressed with unit (i.e. 30m)")
+	}
+
+	if opts.ApprovalTimeout, err = time.ParseDuration(approvalTimeout); err != nil || opts.ApprovalTimeout < 0 {
+		return nil, fmt.Errorf("approval timeout must be expressed with unit (i.e. 10m)")
+	}
+
+	if !util.ListContainsElement(options.SummaryFormats, opts.SummaryFormat) {
+		return nil, tgerrors.WithStackTrace(ErrInvalidSummaryFormat(opts.SummaryFormat))
+	}
+
+	if !util.ListContainsElement(options.LogFormats, opts.LogFormat) {
+		return nil, tgerrors.WithStackTrace(ErrInvalidLogFormat(opts.LogFormat))
+	}
+
 	opts.Logger.SetDefaultConsoleHookLevel(loggingLevel)
 	opts.Logger.SetColor(!util.ListContainsElement(opts.TerraformCliArgs, "-no-color"))
 	if fileLoggingDir != "" {
@@ -237,6 +280,10 @@ func filterTerragruntArgs(args []string) []string {
 			i = i + 1
 			continue
 		}
+		if name := strings.SplitN(argWithoutPrefix, "=", 2)[0]; name != argWithoutPrefix && util.ListContainsElement(allTerragruntStringOpts, name) {
+			// String flags could also be specified as --flag=value
+			continue
+		}
 		if util.ListContainsElement(allTerragruntBooleanOpts, argWithoutPrefix) {
 			// Just skip the boolean flag
 			continue
@@ -264,11 +311,14 @@ func parseBooleanArg(args []string, argName string, envVar string, defaultValue
 	return defaultValue
 }
 
-// Find a string argument (e.g. --foo "VALUE") of the given name in the given list of arguments. If it's present,
+// Find a string argument (e.g. --foo "VALUE" or --foo=VALUE) of the given name in the given list of arguments. If it's present,
 // return its value. If it is present, but has no value, return an error. If it isn't present, return defaultValue.
 func parseStringArg(args []string, argName string, defaultValue string) (string, error) {
 	givenArg := fmt.Sprintf("--%s", argName)
 	for i, arg := range args {
+		if strings.HasPrefix(arg, givenArg+"=") {
+			return strings.TrimPrefix(arg, givenArg+"="), nil
+		}
 		if arg == givenArg {
 			if (i + 1) < len(args) {
 				return args[i+1], nil
@@ -285,3 +335,17 @@ type ErrArgMissingValue string
 func (err ErrArgMissingValue) Error() string {
 	return fmt.Sprintf("You must specify a value for the --%s option", string(err))
 }
+
+// ErrInvalidSummaryFormat indicates that the specified summary format is not supported
+type ErrInvalidSummaryFormat string
+
+func (err ErrInvalidSummaryFormat) Error() string {
+	return fmt.Sprintf("Invalid summary format %s, must be one of %s", string(err), strings.Join(options.SummaryFormats, ", "))
+}
+
+// ErrInvalidLogFormat indicates that the specified log format is not supported
+type ErrInvalidLogFormat string
+
+func (err ErrInvalidLogFormat) Error() string {
+	return fmt.Sprintf("Invalid log format %s, must be one of %s", string(err), strings.Join(options.LogFormats, ", "))
+}
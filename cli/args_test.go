@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/coveooss/terragrunt/v2/config"
 	"github.com/coveooss/terragrunt/v2/options"
@@ -117,6 +118,116 @@ func TestParseTerragruntOptionsFromArgs(t *testing.T) {
 			nil,
 		},
 
+		{
+			[]string{"plan-all", "--terragrunt-summary=detailed", "--terragrunt-source=/some/path"},
+			func() *options.TerragruntOptions {
+				terragruntOptions := mockOptions(util.JoinPath(workingDir, config.DefaultConfigName), workingDir, []string{}, false, "/some/path", false)
+				terragruntOptions.SummaryFormat = options.SummaryDetailed
+				return terragruntOptions
+			}(),
+			nil,
+		},
+
+		{
+			[]string{"--terragrunt-fail-fast", "--terragrunt-max-failures", "3"},
+			func() *options.TerragruntOptions {
+				terragruntOptions := mockOptions(util.JoinPath(workingDir, config.DefaultConfigName), workingDir, []string{}, false, "", false)
+				terragruntOptions.FailFast = true
+				terragruntOptions.MaxFailures = 3
+				return terragruntOptions
+			}(),
+			nil,
+		},
+
+		{
+			[]string{"--terragrunt-module-timeout", "1h30m"},
+			func() *options.TerragruntOptions {
+				terragruntOptions := mockOptions(util.JoinPath(workingDir, config.DefaultConfigName), workingDir, []string{}, false, "", false)
+				terragruntOptions.ModuleTimeout = 90 * time.Minute
+				return terragruntOptions
+			}(),
+			nil,
+		},
+
+		{
+			[]string{"--terragrunt-approval", "https://approvals.example.com/terragrunt", "--terragrunt-approval-timeout", "10m"},
+			func() *options.TerragruntOptions {
+				terragruntOptions := mockOptions(util.JoinPath(workingDir, config.DefaultConfigName), workingDir, []string{}, false, "", false)
+				terragruntOptions.ApprovalHandler = "https://approvals.example.com/terragrunt"
+				terragruntOptions.ApprovalTimeout = 10 * time.Minute
+				return terragruntOptions
+			}(),
+			nil,
+		},
+
+		{
+			[]string{"--terragrunt-progress"},
+			func() *options.TerragruntOptions {
+				terragruntOptions := mockOptions(util.JoinPath(workingDir, config.DefaultConfigName), workingDir, []string{}, false, "", false)
+				terragruntOptions.Progress = true
+				return terragruntOptions
+			}(),
+			nil,
+		},
+
+		{
+			[]string{"destroy", "--terragrunt-allow-destroy-protected"},
+			func() *options.TerragruntOptions {
+				terragruntOptions := mockOptions(util.JoinPath(workingDir, config.DefaultConfigName), workingDir, []string{"destroy"}, false, "", false)
+				terragruntOptions.AllowDestroyProtected = true
+				return terragruntOptions
+			}(),
+			nil,
+		},
+
+		{
+			[]string{"apply", "--terragrunt-approve-each"},
+			func() *options.TerragruntOptions {
+				terragruntOptions := mockOptions(util.JoinPath(workingDir, config.DefaultConfigName), workingDir, []string{"apply"}, false, "", false)
+				terragruntOptions.ApproveEach = true
+				return terragruntOptions
+			}(),
+			nil,
+		},
+
+		{
+			[]string{"--terragrunt-log-format", "json"},
+			func() *options.TerragruntOptions {
+				terragruntOptions := mockOptions(util.JoinPath(workingDir, config.DefaultConfigName), workingDir, []string{}, false, "", false)
+				terragruntOptions.LogFormat = options.LogFormatJSON
+				return terragruntOptions
+			}(),
+			nil,
+		},
+
+		{
+			[]string{"plan-all", "--terragrunt-summary", "json"},
+			func() *options.TerragruntOptions {
+				terragruntOptions := mockOptions(util.JoinPath(workingDir, config.DefaultConfigName), workingDir, []string{}, false, "", false)
+				terragruntOptions.SummaryFormat = options.SummaryJSON
+				return terragruntOptions
+			}(),
+			nil,
+		},
+
+		{
+			[]string{"--terragrunt-summary", "invalid"},
+			nil,
+			ErrInvalidSummaryFormat("invalid"),
+		},
+
+		{
+			[]string{"--terragrunt-summary=invalid"},
+			nil,
+			ErrInvalidSummaryFormat("invalid"),
+		},
+
+		{
+			[]string{"--terragrunt-log-format", "xml"},
+			nil,
+			ErrInvalidLogFormat("xml"),
+		},
+
 		{
 			[]string{"--terragrunt-config"},
 			nil,
@@ -238,6 +349,16 @@ func assertOptionsEqual(t *testing.T, expected options.TerragruntOptions, actual
 	assert.Equal(t, expected.ApplyTemplate, actual.ApplyTemplate, msgAndArgs...)
 	assert.Equal(t, expected.TemplateAdditionalPatterns, actual.TemplateAdditionalPatterns, msgAndArgs...)
 	assert.Equal(t, expected.BootConfigurationPaths, actual.BootConfigurationPaths, msgAndArgs...)
+	assert.Equal(t, expected.SummaryFormat, actual.SummaryFormat, msgAndArgs...)
+	assert.Equal(t, expected.FailFast, actual.FailFast, msgAndArgs...)
+	assert.Equal(t, expected.MaxFailures, actual.MaxFailures, msgAndArgs...)
+	assert.Equal(t, expected.ModuleTimeout, actual.ModuleTimeout, msgAndArgs...)
+	assert.Equal(t, expected.ApprovalHandler, actual.ApprovalHandler, msgAndArgs...)
+	assert.Equal(t, expected.ApprovalTimeout, actual.ApprovalTimeout, msgAndArgs...)
+	assert.Equal(t, expected.Progress, actual.Progress, msgAndArgs...)
+	assert.Equal(t, expected.LogFormat, actual.LogFormat, msgAndArgs...)
+	assert.Equal(t, expected.AllowDestroyProtected, actual.AllowDestroyProtected, msgAndArgs...)
+	assert.Equal(t, expected.ApproveEach, actual.ApproveEach, msgAndArgs...)
 }
 
 func mockOptions(terragruntConfigPath string, workingDir string, terraformCliArgs []string, nonInteractive bool, terragruntSource string, ignoreDependencyErrors bool) *options.TerragruntOptions {
@@ -266,6 +387,8 @@ func TestFilterTerragruntArgs(t *testing.T) {
 		{[]string{"foo", "--terragrunt-non-interactive", "--bar", "--terragrunt-working-dir", "/some/path", "--baz", "--terragrunt-config", fmt.Sprintf("/some/path/%s", config.DefaultConfigName)}, []string{"foo", "--bar", "--baz"}},
 		{[]string{"apply-all", "foo", "bar"}, []string{"foo", "bar"}},
 		{[]string{"foo", "destroy-all", "--foo", "--bar"}, []string{"foo", "--foo", "--bar"}},
+		{[]string{"foo", "--terragrunt-summary=json", "--bar=1"}, []string{"foo", "--bar=1"}},
+		{[]string{"plan-all", "--terragrunt-summary=detailed", "-lock=false"}, []string{"-lock=false"}},
 	}
 
 	for _, testCase := range testCases {
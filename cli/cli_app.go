@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -23,6 +24,7 @@ import (
 	"github.com/rs/xid"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Constant used to define the command line options
@@ -47,12 +49,32 @@ const (
 	optPreBootConfigs                   = "terragrunt-pre-boot-configs"
 	optIncludeEmptyFolders              = "terragrunt-include-empty-folders"
 	optPluginsDirectory                 = "terragrunt-plugins-directory"
+	optReportFile                       = "terragrunt-report-file"
+	optSummaryFormat                    = "terragrunt-summary"
+	optPlanOutputDir                    = "terragrunt-out-dir"
+	optPlanInputDir                     = "terragrunt-plan-dir"
+	optIncludeDirs                      = "terragrunt-include-dir"
+	optExcludeDirs                      = "terragrunt-exclude-dir"
+	optOnlyDependenciesOf               = "terragrunt-only-dependencies-of"
+	optChangedSince                     = "terragrunt-changed-since"
+	optFailFast                         = "terragrunt-fail-fast"
+	optMaxFailures                      = "terragrunt-max-failures"
+	optResume                           = "terragrunt-resume"
+	optModuleTimeout                    = "terragrunt-module-timeout"
+	optProgress                         = "terragrunt-progress"
+	optLogFormat                        = "terragrunt-log-format"
+	optTraceFile                        = "terragrunt-trace-file"
+	optEventWebhook                     = "terragrunt-event-webhook"
+	optAllowDestroyProtected            = "terragrunt-allow-destroy-protected"
+	optApproveEach                      = "terragrunt-approve-each"
+	optApprovalTimeout                  = "terragrunt-approval-timeout"
 )
 
-var allTerragruntBooleanOpts = []string{optNonInteractive, optTerragruntSourceUpdate, optTerragruntIgnoreDependencyErrors, optApplyTemplate, optIncludeEmptyFolders}
-var allTerragruntStringOpts = []string{optTerragruntConfig, optTerragruntTFPath, optWorkingDir, optTerragruntSource, optLoggingLevel, optAWSProfile, optApprovalHandler, optFlushDelay, optNbWorkers, optTemplatePatterns, optBootConfigs, optPreBootConfigs, optLoggingFileDir, optLoggingFileLevel}
+var allTerragruntBooleanOpts = []string{optNonInteractive, optTerragruntSourceUpdate, optTerragruntIgnoreDependencyErrors, optApplyTemplate, optIncludeEmptyFolders, optFailFast, optProgress, optAllowDestroyProtected, optApproveEach}
+var allTerragruntStringOpts = []string{optTerragruntConfig, optTerragruntTFPath, optWorkingDir, optTerragruntSource, optLoggingLevel, optAWSProfile, optApprovalHandler, optFlushDelay, optNbWorkers, optTemplatePatterns, optBootConfigs, optPreBootConfigs, optLoggingFileDir, optLoggingFileLevel, optReportFile, optSummaryFormat, optPlanOutputDir, optPlanInputDir, optIncludeDirs, optExcludeDirs, optOnlyDependenciesOf, optChangedSince, optMaxFailures, optResume, optModuleTimeout, optLogFormat, optTraceFile, optEventWebhook, optApprovalTimeout}
 
 const multiModuleSuffix = "-all"
+const optFlatten = "--flatten" // Used with output-all -json to key the outputs by module_path.output_name
 const cmdInit = "init"
 
 var terraformCommandsThatUseState = []string{
@@ -103,6 +125,7 @@ COMMANDS:
    plan-all                          Display the plans of a 'stack' by running 'terragrunt plan' in each subfolder (with a summary at the end).
    apply-all                         Apply a 'stack' by running 'terragrunt apply' in each subfolder.
    output-all                        Display the outputs of a 'stack' by running 'terragrunt output' in each subfolder (no error if a subfolder doesn't have outputs).
+                                     With -json, the outputs are merged in a single JSON object keyed by module path (--flatten to use module_path.output_name keys).
    destroy-all                       Destroy a 'stack' by running 'terragrunt destroy' in each subfolder in reverse dependency order.
    *-all                             In fact, the -all could be applied on any terraform or custom commands (that's cool).
 
@@ -120,10 +143,29 @@ GLOBAL OPTIONS:
    terragrunt-logging-level             PANIC(0), FATAL (1), ERROR (2), WARNING (3), INFO (4), DEBUG (5), TRACE (6).
    terragrunt-logging-file-dir          Used to configure the directory where (verbose) file logs will be saved.
    terragrunt-logging-file-level        Used to configure the logging level in files.
-   terragrunt-approval                  Program to use for approval. {val} will be replaced by the current terragrunt output. Ex: approval.py --value {val}.
+   terragrunt-approval                  Program or URL (http/https) used to approve the commands, it receives a JSON request and returns a JSON decision (see README).
+   terragrunt-approval-timeout          Maximum duration of a call to the approval handler (i.e. 10m, default 30m).
    terragrunt-flush-delay               Maximum delay on -all commands before printing out traces (INFO) indicating that the process is still alive (default 60s).
    terragrunt-workers                   Number of concurrent workers (default 10).
    terragrunt-include-empty-folders     Do not check if source folders contains terraform files to consider them as part of the stack.
+   terragrunt-report-file               Write a report of the *-all commands execution (JUnit format if the extension is .xml, JSON otherwise).
+   terragrunt-summary                   Format of the plan-all summary: simple (default), detailed (list the changed resources) or json.
+   terragrunt-out-dir                   Save the plan of each module (plan-all) in the specified folder.
+   terragrunt-plan-dir                  Apply the plans saved by plan-all in the specified folder (apply-all). Fails if the stack has changed since the plan, the modules that have not been planned are skipped.
+   terragrunt-include-dir               Comma separated list of glob patterns of the modules to include in *-all commands.
+   terragrunt-exclude-dir               Comma separated list of glob patterns of the modules to exclude from *-all commands.
+   terragrunt-only-dependencies-of      Restrict *-all commands to the specified module and its dependencies.
+   terragrunt-changed-since             Restrict *-all commands to the modules (and their dependents) affected by the files changed since the git reference.
+   terragrunt-fail-fast                 *-all commands stop starting new modules after the first failure (running modules are allowed to finish).
+   terragrunt-max-failures              *-all commands stop starting new modules after the specified number of failures (default 0 = no limit).
+   terragrunt-resume                    Resume the apply-all identified by the specified run id (TERRAGRUNT_RUN_ID), modules already completed by that run are skipped.
+   terragrunt-module-timeout            Maximum duration of the processing of each module in *-all commands (i.e. 30m, default 0 = no limit).
+   terragrunt-progress                  Display a live status of the modules and workers during *-all commands (only if the output is a terminal).
+   terragrunt-log-format                Format of the logs: text (default) or json (one object per line, including the output of the commands).
+   terragrunt-trace-file                Export the trace spans (OpenTelemetry) to the specified JSON file (OTEL_* environment variables are also supported).
+   terragrunt-event-webhook             Post the lifecycle events of *-all commands (stack-started, module-started, module-finished, stack-finished) as JSON to the specified URL.
+   terragrunt-allow-destroy-protected   Allow destroy, destroy-all, apply -destroy and the apply of a destroy plan on the modules protected by prevent_destroy (they are skipped or rejected otherwise).
+   terragrunt-approve-each              Plan each module and ask to approve its plan before applying it (apply-all). The dependents of a rejected module are skipped.
    profile                              Specify an AWS profile to use.
 
 ENVIRONMENT VARIABLES:
@@ -132,7 +174,13 @@ ENVIRONMENT VARIABLES:
 	  TERRAGRUNT_INCLUDE_EMPTY_FOLDERS, TERRAGRUNT_BOOT_CONFIGS, TERRAGRUNT_PREBOOT_CONFIGS,
 	  TERRAGRUNT_LOGGING_LEVEL, TERRAGRUNT_LOGGING_FILE_DIR, TERRAGRUNT_LOGGING_FILE_LEVEL,
 	  TERRAGRUNT_TEMPLATE, TERRAGRUNT_TEMPLATE_PATTERNS, TERRAGRUNT_CACHE_FOLDER,
-	  TERRAGRUNT_FLUSH_DELAY, TERRAGRUNT_WORKERS
+	  TERRAGRUNT_FLUSH_DELAY, TERRAGRUNT_WORKERS, TERRAGRUNT_REPORT_FILE,
+	  TERRAGRUNT_SUMMARY, TERRAGRUNT_OUT_DIR, TERRAGRUNT_PLAN_DIR,
+	  TERRAGRUNT_INCLUDE_DIRS, TERRAGRUNT_EXCLUDE_DIRS, TERRAGRUNT_ONLY_DEPENDENCIES_OF, TERRAGRUNT_CHANGED_SINCE,
+	  TERRAGRUNT_FAIL_FAST, TERRAGRUNT_MAX_FAILURES, TERRAGRUNT_RESUME,
+	  TERRAGRUNT_MODULE_TIMEOUT, TERRAGRUNT_PROGRESS, TERRAGRUNT_LOG_FORMAT,
+	  TERRAGRUNT_TRACE_FILE, TERRAGRUNT_EVENT_WEBHOOK, TERRAGRUNT_ALLOW_DESTROY_PROTECTED,
+	  TERRAGRUNT_APPROVE_EACH, TERRAGRUNT_APPROVAL, TERRAGRUNT_APPROVAL_TIMEOUT
 	  
    TERRAGRUNT_DEBUG  If set, this enable detailed stack trace in case of application crash
    TERRAGRUNT_CACHE  If set, it defines the root folder used to store temporary files
@@ -219,7 +267,41 @@ func runApp(cliContext *cli.Context) (finalErr error) {
 		return err
 	}
 
-	return runCommand(cliContext.Args().First(), terragruntOptions)
+	shutdownTracing, err := initTracing(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing()
+	defer func() {
+		if err := util.SaveTimingHistory(); err != nil {
+			terragruntOptions.Logger.Warningf("Unable to save the timing history: %v", err)
+		}
+	}()
+
+	command := cliContext.Args().First()
+	endSpan := terragruntOptions.StartSpan("terragrunt "+command,
+		attribute.String("terragrunt.run_id", terragruntRunID),
+		attribute.String("terragrunt.args", strings.Join(os.Args[1:], " ")),
+		attribute.String("terragrunt.working_dir", terragruntOptions.WorkingDir),
+	)
+	defer func() { endSpan(finalErr) }()
+
+	if terragruntOptions.EventWebhook != "" {
+		terragruntOptions.AddEventListener(newEventWebhook(terragruntOptions))
+	}
+
+	return runCommand(command, terragruntOptions)
+}
+
+// Returns an event listener that posts the events to the webhook specified by --terragrunt-event-webhook. The errors
+// are only reported as warnings since the notifications must not interrupt the execution.
+func newEventWebhook(terragruntOptions *options.TerragruntOptions) options.EventListener {
+	url, logger := terragruntOptions.EventWebhook, terragruntOptions.Logger
+	return func(event options.Event) {
+		if err := util.PostJSON(url, nil, event, util.DefaultWebhookTimeout); err != nil {
+			logger.Warningf("Unable to post the event %s: %v", event.Type, err)
+		}
+	}
 }
 
 // runCommand runs one or many terraform commands based on the type of
@@ -304,6 +386,14 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 
 	// Check if the current command is an extra command
 	actualCommand := conf.ExtraCommands.ActualCommand(terragruntOptions.TerraformCliArgs[0])
+	if conf.IsProtected() && !terragruntOptions.AllowDestroyProtected {
+		if isDestroy(actualCommand.Command, terragruntOptions.TerraformCliArgs) {
+			return tgerrors.WithStackTrace(errProtectedModule(terragruntOptions.WorkingDir))
+		}
+		if actualCommand.Command == "plan" && hasDestroyFlag(terragruntOptions.TerraformCliArgs) {
+			terragruntOptions.Logger.Warningf("Module %s is protected by prevent_destroy, its destroy plan will be refused by apply unless --%s is specified", terragruntOptions.WorkingDir, optAllowDestroyProtected)
+		}
+	}
 	ignoreError := actualCommand.Extra != nil && actualCommand.Extra.IgnoreError
 	terragruntOptions.Env[options.EnvCommand] = terragruntOptions.TerraformCliArgs[0]
 
@@ -370,6 +460,12 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 		if stopOnError(err) {
 			return
 		}
+		if isSavedPlanApply(terragruntOptions.TerraformCliArgs) {
+			// Variables cannot be specified when applying a saved plan
+			extraArgs = util.FilterList(extraArgs, func(arg string) bool {
+				return !strings.HasPrefix(arg, "-var=") && !strings.HasPrefix(arg, "-var-file=")
+			})
+		}
 
 		args = append(args, extraArgs...)
 		if commandLength <= len(terragruntOptions.TerraformCliArgs) {
@@ -561,7 +657,9 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 		// Passing a plugin-dir explicitly disallows downloads correctly
 		initArgs = append(initArgs, fmt.Sprintf("-plugin-dir=%s", terragruntOptions.PluginsDirectory))
 	}
-	if err = shell.NewTFCmd(terragruntOptions).Args(initArgs...).WithRetries(3).LogOutput(logrus.DebugLevel); stopOnError(err) {
+	endInitSpan := terragruntOptions.StartSpan("terraform init", attribute.StringSlice("terragrunt.args", initArgs))
+	err = shell.NewTFCmd(terragruntOptions).Args(initArgs...).WithRetries(3).LogOutput(logrus.DebugLevel)
+	if endInitSpan(err); stopOnError(err) {
 		return
 	}
 
@@ -570,8 +668,18 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 		return
 	}
 
+	if conf.IsProtected() && !terragruntOptions.AllowDestroyProtected && actualCommand.Extra == nil && actualCommand.Command == "apply" && isSavedPlanApply(terragruntOptions.TerraformCliArgs) {
+		// The saved plan may have been created by plan -destroy
+		planFile := terragruntOptions.TerraformCliArgs[len(terragruntOptions.TerraformCliArgs)-1]
+		if destroy, err := isDestroyPlan(terragruntOptions, planFile); err != nil {
+			return err
+		} else if destroy {
+			return tgerrors.WithStackTrace(errProtectedModule(terragruntOptions.WorkingDir))
+		}
+	}
+
 	isApply := actualCommand.Command == "apply" || (actualCommand.Extra != nil && actualCommand.Extra.ActAs == "apply")
-	if terragruntOptions.NonInteractive && isApply && !util.ListContainsElement(terragruntOptions.TerraformCliArgs, "-auto-approve") {
+	if terragruntOptions.NonInteractive && isApply && !isSavedPlanApply(terragruntOptions.TerraformCliArgs) && !util.ListContainsElement(terragruntOptions.TerraformCliArgs, "-auto-approve") {
 		terragruntOptions.TerraformCliArgs = append(terragruntOptions.TerraformCliArgs, "-auto-approve")
 	}
 
@@ -583,7 +691,10 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 		command := actualCommand.Command
 		args := append(actualCommand.Extra.Arguments, terragruntOptions.TerraformCliArgs[1:]...)
 
-		cmd = shell.NewCmd(terragruntOptions, command).Args(args...)
+		extraOptions := terragruntOptions.WithLogFields(logrus.Fields{options.LogFieldExtraCommand: actualCommand.Extra.Name})
+		defer extraOptions.FlushOutput()
+
+		cmd = shell.NewCmd(extraOptions, command).Args(args...)
 		if actualCommand.Extra.ShellCommand {
 			// We must not redirect the stderr on shell command, doing so, remove the prompt
 			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
@@ -599,6 +710,12 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 			cmd.DisplayCommand = actualCommand.Extra.Name
 		}
 
+		timeout, err := actualCommand.Extra.GetTimeout()
+		if err != nil {
+			return err
+		}
+		cmd = cmd.WithTimeout(timeout)
+
 		actualCommand.Command = actualCommand.Extra.ActAs
 	} else {
 		// If the command is 'init', stop here. That's because ConfigureRemoteState above will have already called
@@ -618,7 +735,9 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 		cmd = cmd.Expect(approvalConfig.ExpectStatements, approvalConfig.CompletedStatements)
 	}
 	cmd.LogLevel = logrus.InfoLevel
+	endSpan := terragruntOptions.StartSpan("terraform "+actualCommand.Command, attribute.StringSlice("terragrunt.args", terragruntOptions.TerraformCliArgs))
 	err = shell.FilterPlanError(cmd.Run(), actualCommand.Command)
+	endSpan(err)
 
 	exitCode, errCode := shell.GetExitCode(err)
 	if errCode != nil {
@@ -632,6 +751,82 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 	return
 }
 
+// Returns true if the command destroys the resources of the module (destroy or apply -destroy). The saved plans are
+// checked by isDestroyPlan.
+func isDestroy(command string, args []string) bool {
+	return command == "destroy" || command == "apply" && hasDestroyFlag(args)
+}
+
+// Returns true if the -destroy flag is set in the arguments
+func hasDestroyFlag(args []string) bool {
+	destroy := false
+	for _, arg := range args {
+		// The last occurrence of the flag wins, as with terraform
+		switch arg {
+		case "-destroy", "--destroy", "-destroy=true", "--destroy=true":
+			destroy = true
+		case "-destroy=false", "--destroy=false":
+			destroy = false
+		}
+	}
+	return destroy
+}
+
+// Returns true if the saved plan destroys all the resources of the module (i.e. it has been created by plan -destroy)
+func isDestroyPlan(terragruntOptions *options.TerragruntOptions, planFile string) (bool, error) {
+	out, err := shell.NewTFCmd(terragruntOptions).Args("show", "-json", planFile).Output()
+	if err != nil {
+		return false, tgerrors.WithStackTrace(fmt.Errorf("unable to read the saved plan %s: %v\n%s", planFile, err, out))
+	}
+	content, err := util.ExtractJSONOutput(out)
+	if err != nil {
+		return false, tgerrors.WithStackTrace(err)
+	}
+	return isDestroyPlanContent(content)
+}
+
+// The subset of the terraform show -json format required to identify a destroy plan
+type destroyPlanContent struct {
+	PlannedValues struct {
+		RootModule struct {
+			Resources    []json.RawMessage `json:"resources"`
+			ChildModules []json.RawMessage `json:"child_modules"`
+		} `json:"root_module"`
+	} `json:"planned_values"`
+	ResourceChanges []struct {
+		Change struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// A destroy plan deletes resources and leaves no resource in the module
+func isDestroyPlanContent(content []byte) (bool, error) {
+	var plan destroyPlanContent
+	if err := json.Unmarshal(content, &plan); err != nil {
+		return false, tgerrors.WithStackTrace(err)
+	}
+	if len(plan.PlannedValues.RootModule.Resources)+len(plan.PlannedValues.RootModule.ChildModules) > 0 {
+		return false, nil
+	}
+	for _, resource := range plan.ResourceChanges {
+		if util.ListContainsElement(resource.Change.Actions, "delete") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Returns true if the arguments represent an apply of a saved plan (i.e. the last argument is a plan file).
+// In that case, terraform does not ask for approval and does not accept variables.
+func isSavedPlanApply(args []string) bool {
+	if len(args) < 2 || args[0] != "apply" {
+		return false
+	}
+	last := args[len(args)-1]
+	return !strings.HasPrefix(last, "-") && util.FileExists(last)
+}
+
 // Execute a command that affects multiple Terraform modules, such as the apply-all or destroy-all command.
 func runMultiModuleCommand(command string, terragruntOptions *options.TerragruntOptions) error {
 	realCommand := strings.TrimSuffix(command, multiModuleSuffix)
@@ -697,6 +892,18 @@ func applyAll(command string, terragruntOptions *options.TerragruntOptions) erro
 		return err
 	}
 
+	if terragruntOptions.ApproveEach {
+		if terragruntOptions.PlanInputDir != "" {
+			return tgerrors.WithStackTrace(fmt.Errorf("--%s cannot be used with --%s", optApproveEach, optPlanInputDir))
+		}
+		// The user is asked to approve the plan of each module instead of the whole stack
+		terragruntOptions.Logger.Info(stack)
+		if err := stack.EnableRunState(command, terragruntOptions); err != nil {
+			return err
+		}
+		return stack.ApplyEach([]string{command, "-input=false"}, terragruntOptions)
+	}
+
 	prompt := fmt.Sprintf("%s\nAre you sure you want to run 'terragrunt apply' in each folder of the stack described above?", stack)
 	shouldApplyAll, err := shell.PromptUserForYesNo(prompt, terragruntOptions)
 	if err != nil {
@@ -704,6 +911,12 @@ func applyAll(command string, terragruntOptions *options.TerragruntOptions) erro
 	}
 
 	if shouldApplyAll {
+		if err := stack.EnableRunState(command, terragruntOptions); err != nil {
+			return err
+		}
+		if terragruntOptions.PlanInputDir != "" {
+			return stack.ApplyPlans([]string{command, "-input=false"}, terragruntOptions)
+		}
 		return stack.RunAll([]string{command, "-input=false"}, terragruntOptions, configstack.NormalOrder)
 	}
 
@@ -719,6 +932,18 @@ func destroyAll(command string, terragruntOptions *options.TerragruntOptions) er
 	}
 
 	prompt := fmt.Sprintf("%s\nWARNING: Are you sure you want to run `terragrunt destroy` in each folder of the stack described above? There is no undo!", stack)
+	if protected := stack.ProtectedModules(); len(protected) > 0 {
+		names := make([]string, len(protected))
+		for i, module := range protected {
+			names[i] = "  - " + util.GetPathRelativeToWorkingDir(module.Path)
+		}
+		if terragruntOptions.AllowDestroyProtected {
+			prompt = fmt.Sprintf("%s\nThe following modules are protected (prevent_destroy) but they will be destroyed since --%s is specified:\n%s", prompt, optAllowDestroyProtected, strings.Join(names, "\n"))
+		} else {
+			stack.SkipProtectedModules(terragruntOptions)
+			prompt = fmt.Sprintf("%s\nThe following modules are protected (prevent_destroy), they will be skipped along with their dependencies:\n%s", prompt, strings.Join(names, "\n"))
+		}
+	}
 	shouldDestroyAll, err := shell.PromptUserForYesNo(prompt, terragruntOptions)
 	if err != nil {
 		return err
@@ -734,12 +959,23 @@ func destroyAll(command string, terragruntOptions *options.TerragruntOptions) er
 // outputAll prints the outputs from all configuration in a stack, in the order
 // specified in the terraform_remote_state dependencies
 func outputAll(command string, terragruntOptions *options.TerragruntOptions) error {
+	// --flatten is handled by terragrunt, so it must not be passed to terraform
+	flatten := util.ListContainsElement(terragruntOptions.TerraformCliArgs, optFlatten)
+	terragruntOptions.TerraformCliArgs = util.RemoveElementFromList(terragruntOptions.TerraformCliArgs, optFlatten)
+	jsonOutput := util.ListContainsElement(terragruntOptions.TerraformCliArgs, "-json")
+	if flatten && !jsonOutput {
+		return tgerrors.WithStackTrace(fmt.Errorf("%s can only be used with -json", optFlatten))
+	}
+
 	stack, err := configstack.FindStackInSubfolders(terragruntOptions)
 	if err != nil {
 		return err
 	}
 
 	terragruntOptions.Logger.Debug(stack)
+	if jsonOutput {
+		return stack.OutputJSON(command, terragruntOptions, flatten)
+	}
 	return stack.Output(command, terragruntOptions)
 }
 
@@ -750,3 +986,9 @@ type unrecognizedCommand string
 func (commandName unrecognizedCommand) Error() string {
 	return fmt.Sprintf("Unrecognized command: %s", string(commandName))
 }
+
+type errProtectedModule string
+
+func (err errProtectedModule) Error() string {
+	return fmt.Sprintf("Module %s is protected by prevent_destroy, use --%s to destroy it", string(err), optAllowDestroyProtected)
+}
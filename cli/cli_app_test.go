@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDestroy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"destroy"}, true},
+		{[]string{"destroy", "-auto-approve"}, true},
+		{[]string{"apply"}, false},
+		{[]string{"apply", "-destroy"}, true},
+		{[]string{"apply", "-destroy=true"}, true},
+		{[]string{"apply", "--destroy"}, true},
+		{[]string{"apply", "-destroy=false"}, false},
+		{[]string{"apply", "-destroy", "-destroy=false"}, false},
+		{[]string{"plan", "-destroy", "-out=destroy.tfplan"}, false},
+		{[]string{"plan", "-destroy=true"}, false},
+		{[]string{"plan", "-out=plan.tfplan"}, false},
+		{[]string{"output", "-destroy"}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, isDestroy(tt.args[0], tt.args))
+		})
+	}
+}
+
+func TestIsDestroyPlanContent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"destroy", `{"planned_values": {"root_module": {}}, "resource_changes": [{"change": {"actions": ["delete"]}}]}`, true},
+		{"several deletes", `{"planned_values": {"root_module": {}}, "resource_changes": [{"change": {"actions": ["delete"]}}, {"change": {"actions": ["delete"]}}]}`, true},
+		{"partial delete", `{"planned_values": {"root_module": {"resources": [{"address": "a"}]}}, "resource_changes": [{"change": {"actions": ["delete"]}}]}`, false},
+		{"remaining child module", `{"planned_values": {"root_module": {"child_modules": [{"address": "module.a"}]}}, "resource_changes": [{"change": {"actions": ["delete"]}}]}`, false},
+		{"create", `{"planned_values": {"root_module": {"resources": [{"address": "a"}]}}, "resource_changes": [{"change": {"actions": ["create"]}}]}`, false},
+		{"empty", `{"planned_values": {"root_module": {}}}`, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			destroy, err := isDestroyPlanContent([]byte(tt.content))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, destroy)
+		})
+	}
+	_, err := isDestroyPlanContent([]byte("not json"))
+	assert.Error(t, err)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/coveooss/terragrunt/v2/util"
 	"github.com/hashicorp/go-getter"
 	urlhelper "github.com/hashicorp/go-getter/helper/url"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // TerraformSource represents information about Terraform source code that needs to be downloaded
@@ -43,7 +44,10 @@ var forcedRegexp = regexp.MustCompile(`^([A-Za-z0-9]+)::(.+)$`)
 //
 // See the processTerraformSource method for how we determine the temporary folder so we can reuse it across multiple
 // runs of Terragrunt to avoid downloading everything from scratch every time.
-func downloadTerraformSource(source *TerraformSource, terragruntOptions *options.TerragruntOptions) error {
+func downloadTerraformSource(source *TerraformSource, terragruntOptions *options.TerragruntOptions) (err error) {
+	endSpan := terragruntOptions.StartSpan("downloadTerraformSource", attribute.String("terragrunt.source", source.CanonicalSourceURL.String()))
+	defer func() { endSpan(err) }()
+
 	if err := downloadTerraformSourceIfNecessary(source, terragruntOptions); err != nil {
 		return err
 	}
@@ -2,14 +2,19 @@ package cli
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/coveooss/gotemplate/v3/hcl"
 	"github.com/coveooss/kingpin/v2"
 	"github.com/coveooss/terragrunt/v2/config"
 	"github.com/coveooss/terragrunt/v2/configstack"
 	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/coveooss/terragrunt/v2/util"
 
 	yaml "gopkg.in/yaml.v3"
 )
@@ -25,9 +30,14 @@ func getStack(terragruntOptions *options.TerragruntOptions) (err error) {
 	)
 
 	run := app.Flag("run", "Run the full stack to get the result instead of just analyzing the dependencies").Short('r').Bool()
-	output := app.Flag("output", "Specify format of the output (hcl, json, yaml)").Short('o').Enum("h", "hcl", "H", "HCL", "j", "json", "J", "JSON", "y", "yml", "yaml", "Y", "YML", "YAML")
+	output := app.Flag("output", "Specify format of the output (hcl, json, yaml, dot, mermaid)").Short('o').Enum("h", "hcl", "H", "HCL", "j", "json", "J", "JSON", "y", "yml", "yaml", "Y", "YML", "YAML", "d", "dot", "DOT", "m", "mermaid", "MERMAID")
 	app.Flag("absolute", "Output absolute path (--abs)").Short('a').BoolVar(&absolute)
 	app.Flag("abs", "").Hidden().BoolVar(&absolute)
+	dependentsOf := app.Flag("dependents-of", "Only output the modules that depend on the specified module").PlaceHolder("path").String()
+	dependenciesOf := app.Flag("dependencies-of", "Only output the modules on which the specified module depends").PlaceHolder("path").String()
+	transitive := app.Flag("transitive", "Include the indirect dependents/dependencies (used with --dependents-of or --dependencies-of)").Short('t').Bool()
+	timings := app.Flag("timings", "Output the last and average duration of each module and the estimated critical path of the stack").Bool()
+	timingsCommand := app.Flag("command", "The command for which the timings are reported (used with --timings)").Default("apply").String()
 	app.HelpFlag.Short('h')
 	if _, err = app.Parse(terragruntOptions.TerraformCliArgs[1:]); err != nil {
 		return
@@ -47,6 +57,14 @@ func getStack(terragruntOptions *options.TerragruntOptions) (err error) {
 		modules = stack.SimpleModules()
 	}
 
+	if modules, err = filterStackModules(modules, *dependentsOf, *dependenciesOf, *transitive, terragruntOptions.WorkingDir); err != nil {
+		return
+	}
+
+	if *timings {
+		return printTimings(modules, *timingsCommand, absolute, terragruntOptions)
+	}
+
 	if !absolute {
 		modules = modules.MakeRelative()
 	}
@@ -62,6 +80,10 @@ func getStack(terragruntOptions *options.TerragruntOptions) (err error) {
 			result, err = json.MarshalIndent(modules, "", "  ")
 		case "y", "yml", "yaml":
 			result, err = yaml.Marshal(modules)
+		case "d", "dot":
+			result = []byte(modules.Dot())
+		case "m", "mermaid":
+			result = []byte(modules.Mermaid())
 		}
 		if err != nil {
 			panic(err)
@@ -75,6 +97,100 @@ func getStack(terragruntOptions *options.TerragruntOptions) (err error) {
 	return nil
 }
 
+// Restrict the list of modules to the dependents or dependencies of the specified modules
+func filterStackModules(modules configstack.SimpleTerraformModules, dependentsOf, dependenciesOf string, transitive bool, workingDir string) (configstack.SimpleTerraformModules, error) {
+	if dependentsOf != "" && dependenciesOf != "" {
+		return nil, tgerrors.WithStackTrace(errConflictingFlags{"dependents-of", "dependencies-of"})
+	}
+
+	query, path := modules.DependentsOf, dependentsOf
+	if dependenciesOf != "" {
+		query, path = modules.DependenciesOf, dependenciesOf
+	}
+	if path == "" {
+		return modules, nil
+	}
+
+	path, err := util.CanonicalPath(path, workingDir)
+	if err != nil {
+		return nil, err
+	}
+	return query(path, transitive)
+}
+
+// Print the duration of the previous executions of the modules and the estimated critical path of the stack
+func printTimings(modules configstack.SimpleTerraformModules, command string, absolute bool, terragruntOptions *options.TerragruntOptions) error {
+	history, err := util.LoadTimingHistory()
+	if err != nil {
+		return err
+	}
+
+	displayPath := func(path string) string {
+		if absolute {
+			return path
+		}
+		return util.GetPathRelativeToWorkingDir(path)
+	}
+	width := len("MODULE")
+	for _, module := range modules {
+		if length := len(displayPath(module.Path)); length > width {
+			width = length
+		}
+		if timings := history.Get(module.Path, command); timings != nil {
+			for hook := range timings.Hooks {
+				if length := len(hook) + 2; length > width {
+					width = length
+				}
+			}
+		}
+	}
+
+	terragruntOptions.Printf("%-*s  %10s  %10s  %4s\n", width, "MODULE", "LAST", "AVERAGE", "RUNS")
+	for _, module := range modules {
+		timings := history.Get(module.Path, command)
+		if timings == nil {
+			terragruntOptions.Printf("%-*s  %10s  %10s  %4d\n", width, displayPath(module.Path), "-", "-", 0)
+			continue
+		}
+		durations := timings.Durations
+		terragruntOptions.Printf("%-*s  %10s  %10s  %4d\n", width, displayPath(module.Path), formatDuration(durations.Last()), formatDuration(durations.Average()), len(durations))
+
+		hooks := make([]string, 0, len(timings.Hooks))
+		for hook := range timings.Hooks {
+			hooks = append(hooks, hook)
+		}
+		sort.Strings(hooks)
+		for _, hook := range hooks {
+			durations := timings.Hooks[hook]
+			terragruntOptions.Printf("%-*s  %10s  %10s  %4d\n", width, "  "+hook, formatDuration(durations.Last()), formatDuration(durations.Average()), len(durations))
+		}
+	}
+
+	criticalPath, duration := modules.CriticalPath(func(path string) time.Duration {
+		if timings := history.Get(path, command); timings != nil {
+			return timings.Durations.Average()
+		}
+		return 0
+	})
+	if duration == 0 {
+		terragruntOptions.Printf("\nThere is no timing history for %s\n", command)
+		return nil
+	}
+	for i := range criticalPath {
+		criticalPath[i] = displayPath(criticalPath[i])
+	}
+	terragruntOptions.Printf("\nEstimated critical path for %s (%s): %s\n", command, formatDuration(duration), strings.Join(criticalPath, " -> "))
+	return nil
+}
+
+// Format the duration with a precision of a second (or a millisecond for short durations)
+func formatDuration(duration time.Duration) string {
+	if duration < time.Second {
+		return duration.Round(time.Millisecond).String()
+	}
+	return duration.Round(time.Second).String()
+}
+
 // Get a list of terraform modules sorted by dependency order (but through real execution of the stack modules)
 // Should give the same result as getStack
 func getStackThroughExecution(terragruntOptions *options.TerragruntOptions) (modules configstack.SimpleTerraformModules, err error) {
@@ -93,3 +209,11 @@ func getStackThroughExecution(terragruntOptions *options.TerragruntOptions) (mod
 	err = runAll(getStackCommand, terragruntOptions)
 	return
 }
+
+// Custom error types
+
+type errConflictingFlags [2]string
+
+func (err errConflictingFlags) Error() string {
+	return fmt.Sprintf("--%s and --%s cannot be used together", err[0], err[1])
+}
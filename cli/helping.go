@@ -27,12 +27,13 @@ func PrintDoc(terragruntOptions *options.TerragruntOptions, conf *config.Terragr
 	hooks := app.Flag("hooks", "List the pre_hook & post_hook configurations").Short('H').Bool()
 	commands := app.Flag("commands", "List the extra_command configurations").Short('C').Bool()
 	approvalConfigs := app.Flag("approval-configs", "List the approval configurations").Bool()
+	eventHooks := app.Flag("events", "List the on_event configurations").Bool()
 	useColor := app.Flag("color", "Enable colors").Short('c').Bool()
 	noColor := app.Flag("no-color", "Disable colors").Short('0').Bool()
 	filters := app.Arg("filters", "Filter the result").Strings()
 	app.HelpFlag.Short('h')
 	app.Parse(terragruntOptions.TerraformCliArgs[1:])
-	all := !(*hooks || *extraArgs || *imports || *variables || *commands || *approvalConfigs)
+	all := !(*hooks || *extraArgs || *imports || *variables || *commands || *approvalConfigs || *eventHooks)
 	if *noColor {
 		color.NoColor = true
 	} else if *useColor {
@@ -83,4 +84,5 @@ func PrintDoc(terragruntOptions *options.TerragruntOptions, conf *config.Terragr
 	}
 	print("Extra commands available", "%s\n", conf.ExtraCommands.Help(*listOnly, *filters...), *commands)
 	print("Approval configurations", "%s\n", conf.ApprovalConfig.Help(*listOnly, *filters...), *approvalConfigs)
+	print("Event hooks", "%s\n", conf.EventHooks.Help(*listOnly, *filters...), *eventHooks)
 }
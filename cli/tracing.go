@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Standard OpenTelemetry environment variables used to select the trace exporters. The other variables (such as
+// OTEL_EXPORTER_OTLP_HEADERS, OTEL_SERVICE_NAME or OTEL_TRACES_SAMPLER) are directly handled by the OpenTelemetry SDK.
+const (
+	envOtelTracesExporter = "OTEL_TRACES_EXPORTER"
+	envOtelEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envOtelTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	envOtelProtocol       = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	envOtelTracesProtocol = "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"
+)
+
+// Maximum delay given to the exporters to send the remaining spans before exiting
+const tracingShutdownTimeout = 10 * time.Second
+
+// Initialize the OpenTelemetry tracer provider if tracing is enabled through the OTEL_* environment variables or the
+// --terragrunt-trace-file option. The returned function must be called before exiting to export the remaining spans.
+func initTracing(terragruntOptions *options.TerragruntOptions) (shutdown func(), err error) {
+	shutdown = func() {}
+	ctx := context.Background()
+
+	var exporters []sdktrace.SpanExporter
+	for _, name := range getTracesExporters() {
+		var exporter sdktrace.SpanExporter
+		switch name {
+		case "none":
+			continue
+		case "otlp":
+			exporter, err = newOTLPExporter(ctx)
+		case "console":
+			exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+		default:
+			err = ErrInvalidTracesExporter(name)
+		}
+		if err != nil {
+			return shutdown, tgerrors.WithStackTrace(err)
+		}
+		exporters = append(exporters, exporter)
+	}
+
+	var traceFile *os.File
+	if terragruntOptions.TraceFile != "" {
+		if traceFile, err = os.Create(terragruntOptions.TraceFile); err != nil {
+			return shutdown, tgerrors.WithStackTrace(err)
+		}
+		exporter, _ := stdouttrace.New(stdouttrace.WithWriter(traceFile))
+		exporters = append(exporters, exporter)
+	}
+
+	if len(exporters) == 0 {
+		return shutdown, nil
+	}
+
+	// The attributes defined in the environment (OTEL_SERVICE_NAME, OTEL_RESOURCE_ATTRIBUTES) have precedence
+	traceResource, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName("terragrunt"),
+			semconv.ServiceVersion(terragruntVersion),
+			attribute.String("terragrunt.run_id", terragruntOptions.Env[options.EnvRunID]),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		terragruntOptions.Logger.Warningf("Unable to initialize the trace resource: %v", err)
+	}
+
+	providerOptions := []sdktrace.TracerProviderOption{sdktrace.WithResource(traceResource)}
+	for _, exporter := range exporters {
+		providerOptions = append(providerOptions, sdktrace.WithBatcher(exporter))
+	}
+	provider := sdktrace.NewTracerProvider(providerOptions...)
+	otel.SetTracerProvider(provider)
+
+	shutdown = func() {
+		ctx, cancel := context.WithTimeout(ctx, tracingShutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			terragruntOptions.Logger.Warningf("Unable to export the trace spans: %v", err)
+		}
+		if traceFile != nil {
+			traceFile.Close()
+		}
+	}
+	return shutdown, nil
+}
+
+// Returns the list of exporters specified by OTEL_TRACES_EXPORTER. If it is not defined, the OTLP exporter is used
+// only if an endpoint has been configured to avoid trying to reach a collector that does not exist.
+func getTracesExporters() []string {
+	if exporters := strings.TrimSpace(os.Getenv(envOtelTracesExporter)); exporters != "" {
+		return strings.Split(exporters, ",")
+	}
+	if os.Getenv(envOtelEndpoint) != "" || os.Getenv(envOtelTracesEndpoint) != "" {
+		return []string{"otlp"}
+	}
+	return nil
+}
+
+// Returns the OTLP exporter for the protocol specified in the environment (the endpoint is also read from the
+// environment and defaults to a collector running on the local host)
+func newOTLPExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	protocol := os.Getenv(envOtelTracesProtocol)
+	if protocol == "" {
+		protocol = os.Getenv(envOtelProtocol)
+	}
+	switch protocol {
+	case "", "http/protobuf":
+		return otlptracehttp.New(ctx)
+	case "grpc":
+		return otlptracegrpc.New(ctx)
+	}
+	return nil, ErrInvalidOTLPProtocol(protocol)
+}
+
+// Custom error types
+
+// ErrInvalidTracesExporter indicates that the exporter specified in OTEL_TRACES_EXPORTER is not supported
+type ErrInvalidTracesExporter string
+
+func (err ErrInvalidTracesExporter) Error() string {
+	return fmt.Sprintf("Invalid traces exporter %s in %s, must be otlp, console or none", string(err), envOtelTracesExporter)
+}
+
+// ErrInvalidOTLPProtocol indicates that the protocol specified in OTEL_EXPORTER_OTLP_PROTOCOL is not supported
+type ErrInvalidOTLPProtocol string
+
+func (err ErrInvalidOTLPProtocol) Error() string {
+	return fmt.Sprintf("Invalid OTLP protocol %s in %s, must be http/protobuf or grpc", string(err), envOtelProtocol)
+}
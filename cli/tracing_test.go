@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTracesExporters(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected []string
+	}{
+		{"Not configured", nil, nil},
+		{"Explicit", map[string]string{envOtelTracesExporter: "otlp,console"}, []string{"otlp", "console"}},
+		{"Endpoint", map[string]string{envOtelEndpoint: "http://localhost:4318"}, []string{"otlp"}},
+		{"Traces endpoint", map[string]string{envOtelTracesEndpoint: "http://localhost:4318/v1/traces"}, []string{"otlp"}},
+		{"Disabled", map[string]string{envOtelTracesExporter: "none", envOtelEndpoint: "http://localhost:4318"}, []string{"none"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{envOtelTracesExporter, envOtelEndpoint, envOtelTracesEndpoint} {
+				t.Setenv(name, tt.env[name])
+			}
+			assert.Equal(t, tt.expected, getTracesExporters())
+		})
+	}
+}
+
+func TestInitTracing(t *testing.T) {
+	t.Setenv(envOtelTracesExporter, "")
+	t.Setenv(envOtelEndpoint, "")
+	t.Setenv(envOtelTracesEndpoint, "")
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	terragruntOptions.Env[options.EnvRunID] = "run"
+	terragruntOptions.TraceFile = filepath.Join(t.TempDir(), "trace.json")
+
+	shutdown, err := initTracing(terragruntOptions)
+	assert.NoError(t, err)
+	endRoot := terragruntOptions.StartSpan("root")
+	endChild := terragruntOptions.StartSpan("child")
+	endChild(fmt.Errorf("failure"))
+	endRoot(nil)
+	shutdown()
+
+	content, err := os.ReadFile(terragruntOptions.TraceFile)
+	assert.NoError(t, err)
+	type keyValue struct {
+		Key   string
+		Value struct{ Value interface{} }
+	}
+	type span struct {
+		Name        string
+		SpanContext struct{ SpanID string }
+		Parent      struct{ SpanID string }
+		Status      struct{ Code string }
+		Resource    []keyValue
+	}
+	spans := map[string]span{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var s span
+		assert.NoError(t, json.Unmarshal([]byte(line), &s))
+		spans[s.Name] = s
+	}
+	assert.Len(t, spans, 2)
+	assert.Equal(t, spans["root"].SpanContext.SpanID, spans["child"].Parent.SpanID)
+	assert.Equal(t, "Error", spans["child"].Status.Code)
+	assert.Equal(t, "Unset", spans["root"].Status.Code)
+	runID := keyValue{Key: "terragrunt.run_id"}
+	runID.Value.Value = "run"
+	assert.Contains(t, spans["root"].Resource, runID)
+}
+
+func TestInitTracingInvalidExporter(t *testing.T) {
+	t.Setenv(envOtelTracesExporter, "zipkin")
+
+	_, err := initTracing(options.NewTerragruntOptionsForTest("terragrunt.hcl"))
+	assert.Equal(t, ErrInvalidTracesExporter("zipkin"), tgerrors.Unwrap(err))
+}
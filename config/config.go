@@ -24,6 +24,7 @@ import (
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/zclconf/go-cty/cty"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -39,8 +40,11 @@ type TerragruntConfig struct {
 	ApprovalConfig          ApprovalConfigList          `hcl:"approval_config,block" export:"true"`
 	AssumeRole              []string                    `export:"true"`
 	AssumeRoleDurationHours *int                        `hcl:"assume_role_duration_hours,attr" export:"true"`
+	ConcurrencyGroup        string                      `hcl:"concurrency_group,optional" export:"true"`
+	ConcurrencyLimits       map[string]int              `hcl:"concurrency_limits,optional" export:"true"`
 	Dependencies            *ModuleDependencies         `hcl:"dependencies,block" export:"true"`
 	Description             string                      `hcl:"description,optional" export:"true"`
+	EventHooks              EventHookList               `hcl:"on_event,block" export:"true"`
 	ExportVariablesConfigs  []ExportVariablesConfig     `hcl:"export_variables,block" export:"true"`
 	ExportConfigConfigs     []ExportVariablesConfig     `hcl:"export_config,block" export:"true"`
 	ExtraArgs               TerraformExtraArgumentsList `hcl:"extra_arguments,block" export:"true"`
@@ -50,6 +54,7 @@ type TerragruntConfig struct {
 	Inputs                  map[string]interface{}
 	PreHooks                HookList      `hcl:"pre_hook,block" export:"true"`
 	PostHooks               HookList      `hcl:"post_hook,block" export:"true"`
+	PreventDestroy          *bool         `hcl:"prevent_destroy,attr" export:"true"`
 	RemoteState             *remote.State `hcl:"remote_state,block" export:"true"`
 	RunConditions           RunConditions
 	Terraform               *TerraformConfig `hcl:"terraform,block" export:"true"`
@@ -59,6 +64,9 @@ type TerragruntConfig struct {
 	InputsHclDefinition        cty.Value                    `hcl:"inputs,optional"`
 	RunConditionsHclDefinition []runConditionsHclDefinition `hcl:"run_conditions,block"`
 
+	// ConfigFiles lists the local configuration files that have been read to build the config (including included and boot files)
+	ConfigFiles []string
+
 	options *options.TerragruntOptions
 }
 
@@ -66,6 +74,11 @@ func (conf TerragruntConfig) String() string {
 	return collections.PrettyPrintStruct(conf)
 }
 
+// IsProtected returns true if the module must not be destroyed (prevent_destroy)
+func (conf TerragruntConfig) IsProtected() bool {
+	return conf.PreventDestroy != nil && *conf.PreventDestroy
+}
+
 // ExtraArguments processes the extra_arguments defined in the terraform section of the config file
 func (conf TerragruntConfig) ExtraArguments(source string) ([]string, error) {
 	return conf.ExtraArgs.Filter(source)
@@ -114,7 +127,8 @@ type TerragruntConfigFile struct {
 	// In that case, most of the config goes down to TerragruntConfig
 	// https://godoc.org/github.com/hashicorp/hcl/v2/gohcl
 	TerragruntConfig `hcl:",remain"`
-	Include          *IncludeConfig `hcl:"include,block"`
+	Include          *IncludeConfig    `hcl:"include,block"`
+	DependencyBlocks []DependencyBlock `hcl:"dependency,block"`
 }
 
 func (tcf TerragruntConfigFile) String() string {
@@ -147,7 +161,19 @@ func (tcf *TerragruntConfigFile) convertToTerragruntConfig(terragruntOptions *op
 		tcf.ExtraArgs = append(tcf.ExtraArgs, tcf.Terraform.LegacyExtraArgs...)
 	}
 
-	if !tcf.InputsHclDefinition.IsNull() {
+	// The modules referred by dependency blocks must also be applied before the current module
+	for _, dependency := range tcf.DependencyBlocks {
+		if tcf.Dependencies == nil {
+			tcf.Dependencies = &ModuleDependencies{}
+		}
+		folder := filepath.Dir(dependency.configPath(filepath.Dir(tcf.Path)))
+		if !util.ListContainsElement(tcf.Dependencies.Paths, folder) {
+			tcf.Dependencies.Paths = append(tcf.Dependencies.Paths, folder)
+		}
+	}
+
+	// The inputs are not wholly known if they refer to dependency outputs that have not been read
+	if !tcf.InputsHclDefinition.IsNull() && tcf.InputsHclDefinition.IsWhollyKnown() {
 		if err := util.FromCtyValue(tcf.InputsHclDefinition, &tcf.Inputs); err != nil {
 			return nil, err
 		}
@@ -158,6 +184,7 @@ func (tcf *TerragruntConfigFile) convertToTerragruntConfig(terragruntOptions *op
 	tcf.ImportFiles.baseInit(tcf)
 	tcf.ImportVariables.baseInit(tcf)
 	tcf.ApprovalConfig.baseInit(tcf)
+	tcf.EventHooks.baseInit(tcf)
 	tcf.PreHooks.init(tcf, PreHookType)
 	tcf.PostHooks.init(tcf, PostHookType)
 	tcf.RunConditions = RunConditions{}
@@ -196,7 +223,9 @@ func (tcf *TerragruntConfigFile) GetSourceFolder(name string, source string, fai
 	terragruntOptions := tcf.options
 
 	if source != "" {
+		endSpan := terragruntOptions.StartSpan("GetSource", attribute.String("terragrunt.source", source))
 		sourceFolder, err := util.GetSource(source, filepath.Dir(tcf.Path), terragruntOptions.Logger, fileRegex)
+		endSpan(err)
 		if err != nil {
 			if failIfNotFound {
 				return "", err
@@ -295,6 +324,15 @@ func ParseConfigFile(terragruntOptions *options.TerragruntOptions, include Inclu
 		}
 	}
 
+	spanName := "ParseConfigFile"
+	if include.isBootstrap {
+		spanName += " (boot config)"
+	} else if include.isIncludedBy != nil {
+		spanName += " (include)"
+	}
+	endSpan := terragruntOptions.StartSpan(spanName, attribute.String("terragrunt.config.path", include.Path), attribute.String("terragrunt.config.source", include.Source))
+	defer func() { endSpan(err) }()
+
 	config = &TerragruntConfig{options: terragruntOptions}
 	if include.isIncludedBy == nil && !include.isBootstrap {
 		if err = config.loadBootConfigs(terragruntOptions, &IncludeConfig{isBootstrap: true}, terragruntOptions.PreBootConfigurationPaths); err != nil {
@@ -308,7 +346,9 @@ func ParseConfigFile(terragruntOptions *options.TerragruntOptions, include Inclu
 		configString, err = util.ReadFileAsString(include.Path)
 		source = include.Path
 	} else {
+		endGetSource := terragruntOptions.StartSpan("GetSource", attribute.String("terragrunt.source", include.Source))
 		include.Path, configString, err = util.ReadFileAsStringFromSource(include.Source, include.Path, terragruntOptions.Logger)
+		endGetSource(err)
 		source = include.Path
 	}
 	if err != nil {
@@ -366,8 +406,12 @@ func ParseConfigFile(terragruntOptions *options.TerragruntOptions, include Inclu
 		}
 	}
 	config.mergeIncludedConfig(*userConfig)
+	if include.Source == "" {
+		config.ConfigFiles = append([]string{include.Path}, config.ConfigFiles...)
+	}
 
-	if include.isIncludedBy == nil {
+	if include.isIncludedBy == nil && !terragruntOptions.IgnoreDependencyOutputs {
+		// The config is not cached if the dependency outputs have not been resolved
 		configFiles.Store(include.Path, []interface{}{configString, config})
 	}
 
@@ -502,6 +546,9 @@ func parseHcl(content string, filename string, out interface{}, resolveContext *
 	if err != nil {
 		return err
 	}
+	if funcs, err = resolveContext.resolveDependencies(file.Body, funcs); err != nil {
+		return err
+	}
 	if decodeDiagnostics := gohcl.DecodeBody(file.Body, funcs, out); decodeDiagnostics != nil && decodeDiagnostics.HasErrors() {
 		return decodeDiagnostics
 	}
@@ -509,7 +556,13 @@ func parseHcl(content string, filename string, out interface{}, resolveContext *
 	return nil
 }
 
-func (conf *TerragruntConfig) loadBootConfigs(terragruntOptions *options.TerragruntOptions, include *IncludeConfig, bootConfigsPath []string) error {
+func (conf *TerragruntConfig) loadBootConfigs(terragruntOptions *options.TerragruntOptions, include *IncludeConfig, bootConfigsPath []string) (err error) {
+	if len(bootConfigsPath) == 0 {
+		return nil
+	}
+	endSpan := terragruntOptions.StartSpan("loadBootConfigs", attribute.StringSlice("terragrunt.boot_configs", bootConfigsPath))
+	defer func() { endSpan(err) }()
+
 	for _, bootstrapFile := range collections.AsList(bootConfigsPath).Reverse().Strings() {
 		bootstrapFile = strings.TrimSpace(bootstrapFile)
 		if bootstrapFile != "" {
@@ -523,7 +576,9 @@ func (conf *TerragruntConfig) loadBootConfigs(terragruntOptions *options.Terragr
 				bootstrapDir = bootstrapDir + "/"
 			}
 
+			endGetSource := terragruntOptions.StartSpan("GetSource", attribute.String("terragrunt.source", bootstrapDir))
 			sourcePath, err := util.GetSource(bootstrapDir, terragruntOptions.WorkingDir, terragruntOptions.Logger, "")
+			endGetSource(err)
 			if err != nil {
 				return err
 			}
@@ -597,6 +652,23 @@ func (conf *TerragruntConfig) mergeIncludedConfig(includedConfig TerragruntConfi
 		conf.AssumeRoleDurationHours = includedConfig.AssumeRoleDurationHours
 	}
 
+	if conf.PreventDestroy == nil {
+		conf.PreventDestroy = includedConfig.PreventDestroy
+	}
+
+	if conf.ConcurrencyGroup == "" {
+		conf.ConcurrencyGroup = includedConfig.ConcurrencyGroup
+	}
+
+	for group, limit := range includedConfig.ConcurrencyLimits {
+		if _, exist := conf.ConcurrencyLimits[group]; !exist {
+			if conf.ConcurrencyLimits == nil {
+				conf.ConcurrencyLimits = make(map[string]int, len(includedConfig.ConcurrencyLimits))
+			}
+			conf.ConcurrencyLimits[group] = limit
+		}
+	}
+
 	if includedConfig.Inputs != nil {
 		conf.Inputs, _ = utils.MergeDictionaries(conf.Inputs, includedConfig.Inputs)
 
@@ -614,12 +686,19 @@ func (conf *TerragruntConfig) mergeIncludedConfig(includedConfig TerragruntConfi
 		conf.ExportConfigConfigs = append(conf.ExportConfigConfigs, includedConfig.ExportConfigConfigs...)
 	}
 
+	for _, file := range includedConfig.ConfigFiles {
+		if !util.ListContainsElement(conf.ConfigFiles, file) {
+			conf.ConfigFiles = append(conf.ConfigFiles, file)
+		}
+	}
+
 	conf.ExtraArgs.Merge(includedConfig.ExtraArgs)
 	conf.RunConditions.Merge(includedConfig.RunConditions)
 	conf.ImportFiles.Merge(includedConfig.ImportFiles)
 	conf.ImportVariables.Merge(includedConfig.ImportVariables)
 	conf.ExtraCommands.Merge(includedConfig.ExtraCommands)
 	conf.ApprovalConfig.Merge(includedConfig.ApprovalConfig)
+	conf.EventHooks.Merge(includedConfig.EventHooks)
 	conf.PreHooks.MergePrepend(includedConfig.PreHooks)
 	conf.PostHooks.MergeAppend(includedConfig.PostHooks)
 }
@@ -33,16 +33,32 @@ func (c RunConditions) String() (result string) {
 }
 
 // ShouldRun returns whether or not the current project should be run based on its run conditions and the variables in its options.
+// The reasons why the project is ignored are logged.
 func (c RunConditions) ShouldRun() bool {
+	return c.evaluate(true)
+}
+
+// IsSatisfied returns whether or not the current project should be run based on its run conditions, without logging anything.
+func (c RunConditions) IsSatisfied() bool {
+	return c.evaluate(false)
+}
+
+func (c RunConditions) evaluate(logReasons bool) bool {
+	warn := func(options *options.TerragruntOptions, format string, args ...interface{}) {
+		if logReasons {
+			options.Logger.Warningf(format, args...)
+		}
+	}
+
 	answer := true
 	for _, deny := range c.Denies {
 		ok, err := deny.isTrue()
 		if err != nil {
-			deny.options().Logger.Warningf("Ignoring project because %v in %s", err, deny)
+			warn(deny.options(), "Ignoring project because %v in %s", err, deny)
 			answer = false
 		}
 		if ok {
-			deny.options().Logger.Warningf("Ignoring project because of ignore rule %s", deny)
+			warn(deny.options(), "Ignoring project because of ignore rule %s", deny)
 			answer = false
 		}
 	}
@@ -60,7 +76,7 @@ func (c RunConditions) ShouldRun() bool {
 	answer = false
 	for _, allow := range c.Allows {
 		if result, err := allow.isTrue(); err != nil {
-			allow.options().Logger.Warningf("Ignoring project because %v in %s", err, allow)
+			warn(allow.options(), "Ignoring project because %v in %s", err, allow)
 			hasErr = true
 		} else if result {
 			answer = true
@@ -72,7 +88,7 @@ func (c RunConditions) ShouldRun() bool {
 	}
 
 	if !answer {
-		options.Logger.Warningf("Ignoring project because running condition is not met: %s", c.rules(c.Allows))
+		warn(options, "Ignoring project because running condition is not met: %s", c.rules(c.Allows))
 	}
 	return false
 }
@@ -320,6 +320,7 @@ func TestRunConditions(t *testing.T) {
 				assert.Fail(t, fmt.Sprintf("Caught error while parsing config: %v", err))
 			} else {
 				assert.Equal(t, tt.expected, config.RunConditions.ShouldRun())
+				assert.Equal(t, tt.expected, config.RunConditions.IsSatisfied())
 			}
 		})
 	}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/coveooss/terragrunt/v2/util"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// DependencyBlock represents another module on which the current module depends. The outputs of that module are
+// available in the config and in the templates through dependency.<name>.outputs.<output_name>.
+type DependencyBlock struct {
+	Name                                string    `hcl:"name,label"`
+	ConfigPath                          string    `hcl:"config_path"`
+	SkipOutputs                         bool      `hcl:"skip_outputs,optional"`
+	MockOutputs                         cty.Value `hcl:"mock_outputs,optional"`
+	MockOutputsAllowedTerraformCommands []string  `hcl:"mock_outputs_allowed_terraform_commands,optional"`
+}
+
+// The commands for which the mock outputs are used if mock_outputs_allowed_terraform_commands is not specified
+var defaultMockOutputsAllowedCommands = []string{"plan", "validate"}
+
+// Returns the path of the config file of the dependency (config_path could either be a folder or a file)
+func (dependency DependencyBlock) configPath(folder string) string {
+	path := dependency.ConfigPath
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(folder, path)
+	}
+	if stat, err := os.Stat(path); err != nil || stat.IsDir() {
+		path = filepath.Join(path, DefaultConfigName)
+	}
+	return filepath.Clean(path)
+}
+
+// Returns the mock outputs (nil if there is no mock outputs)
+func (dependency DependencyBlock) mockOutputs() (mocks map[string]interface{}, err error) {
+	if dependency.MockOutputs.IsNull() {
+		return nil, nil
+	}
+	if err = util.FromCtyValue(dependency.MockOutputs, &mocks); err != nil {
+		return nil, tgerrors.WithStackTrace(err)
+	}
+	if mocks == nil {
+		mocks = map[string]interface{}{}
+	}
+	return mocks, nil
+}
+
+// Returns true if the mock outputs can be used for the command
+func (dependency DependencyBlock) mocksAllowed(command string) bool {
+	allowed := dependency.MockOutputsAllowedTerraformCommands
+	if allowed == nil {
+		allowed = defaultMockOutputsAllowedCommands
+	}
+	return util.ListContainsElement(allowed, command)
+}
+
+// Reads the dependency blocks of the config and returns a new evaluation context where the outputs of the dependencies
+// are defined. The dependency blocks must be evaluated before the rest of the config since the other attributes (i.e.
+// inputs) may refer to their outputs.
+func (ctx *resolveContext) resolveDependencies(body hcl.Body, evalContext *hcl.EvalContext) (*hcl.EvalContext, error) {
+	var partialConfig struct {
+		Dependencies []DependencyBlock `hcl:"dependency,block"`
+		Remain       hcl.Body          `hcl:",remain"`
+	}
+	if diagnostics := gohcl.DecodeBody(body, evalContext, &partialConfig); diagnostics != nil && diagnostics.HasErrors() {
+		return nil, diagnostics
+	}
+	if len(partialConfig.Dependencies) == 0 {
+		return evalContext, nil
+	}
+
+	unknownOutputs := map[string]cty.Value{}
+	for _, dependency := range partialConfig.Dependencies {
+		configPath := dependency.configPath(ctx.getCurrentDir())
+		outputs, err := ctx.getDependencyOutputs(dependency, configPath)
+		if err != nil {
+			return nil, err
+		}
+		if outputs == nil {
+			// The outputs are not read, so any reference to them is evaluated as an unknown value
+			unknownOutputs[dependency.Name] = cty.ObjectVal(map[string]cty.Value{
+				"config_path": cty.StringVal(configPath),
+				"outputs":     cty.DynamicVal,
+			})
+			continue
+		}
+		if ctx.options.DependencyOutputs == nil {
+			ctx.options.DependencyOutputs = map[string]interface{}{}
+		}
+		ctx.options.DependencyOutputs[dependency.Name] = map[string]interface{}{
+			"config_path": configPath,
+			"outputs":     outputs,
+		}
+	}
+
+	evalContext, err := ctx.getHelperFunctionsHCLContext()
+	if err != nil || len(unknownOutputs) == 0 {
+		return evalContext, err
+	}
+	if knownOutputs, found := evalContext.Variables["dependency"]; found {
+		for name, value := range knownOutputs.AsValueMap() {
+			unknownOutputs[name] = value
+		}
+	}
+	evalContext.Variables["dependency"] = cty.ObjectVal(unknownOutputs)
+	return evalContext, nil
+}
+
+// Returns the outputs of the dependency or its mock outputs if the dependency has not been applied yet. Returns nil if
+// the outputs are not read and there is no mock outputs.
+func (ctx *resolveContext) getDependencyOutputs(dependency DependencyBlock, configPath string) (map[string]interface{}, error) {
+	mocks, err := dependency.mockOutputs()
+	if err != nil {
+		return nil, err
+	}
+	if ctx.options.IgnoreDependencyOutputs {
+		return mocks, nil
+	}
+	if dependency.SkipOutputs {
+		if mocks == nil {
+			mocks = map[string]interface{}{}
+		}
+		return mocks, nil
+	}
+
+	outputs, err := readDependencyOutputs(ctx.options, configPath)
+	if err == nil && len(outputs) > 0 {
+		return outputs, nil
+	}
+	if command := util.IndexOrDefault(ctx.options.TerraformCliArgs, 0, ""); mocks != nil && dependency.mocksAllowed(command) {
+		ctx.options.Logger.Infof("Using the mock outputs of dependency %s since %s has no outputs", dependency.Name, util.GetPathRelativeToWorkingDir(filepath.Dir(configPath)))
+		return mocks, nil
+	}
+	if err != nil {
+		return nil, tgerrors.WithStackTrace(errDependencyOutputs{dependency.Name, configPath, err})
+	}
+	return outputs, nil
+}
+
+// Runs terragrunt output -json on the dependency and returns the value of its outputs (the outputs that have already
+// been read during the current run are taken from the output cache and the concurrent reads of the same dependency are
+// done only once)
+func readDependencyOutputs(terragruntOptions *options.TerragruntOptions, configPath string) (map[string]interface{}, error) {
+	modulePath := filepath.Dir(configPath)
+	content, cached, err := terragruntOptions.OutputCache.GetOrLoad(modulePath, func() (json.RawMessage, error) {
+		return runOutputCommand(terragruntOptions, configPath)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached {
+		terragruntOptions.Logger.Debugf("Using the cached outputs of %s", util.GetPathRelativeToWorkingDir(modulePath))
+	}
+	return util.ParseTerraformOutputs(string(content))
+}
+
+// Runs terragrunt output -json on the dependency and returns the JSON outputs
+func runOutputCommand(terragruntOptions *options.TerragruntOptions, configPath string) (json.RawMessage, error) {
+	dependencyOptions := terragruntOptions.Clone(configPath)
+	dependencyOptions.TerraformCliArgs = []string{"output", "-json"}
+	// We only need the state of the dependency, so its own dependencies are not evaluated
+	dependencyOptions.IgnoreDependencyOutputs = true
+	out := new(outputBuffer)
+	dependencyOptions.Writer = out
+
+	terragruntOptions.Logger.Debugf("Reading the outputs of %s", util.GetPathRelativeToWorkingDir(dependencyOptions.WorkingDir))
+	if err := dependencyOptions.RunTerragrunt(dependencyOptions); err != nil {
+		return nil, err
+	}
+	content, err := util.ExtractJSONOutput(out.String())
+	if err != nil {
+		return nil, tgerrors.WithStackTrace(err)
+	}
+	return content, nil
+}
+
+// outputBuffer collects the output of a command (the writers of the options must be closable)
+type outputBuffer struct{ bytes.Buffer }
+
+func (outputBuffer) Close() error { return nil }
+
+// Custom error types
+
+type errDependencyOutputs struct {
+	name       string
+	configPath string
+	err        error
+}
+
+func (err errDependencyOutputs) Error() string {
+	return fmt.Sprintf("Unable to read the outputs of dependency %s (%s): %v", err.name, err.configPath, err.err)
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyOutputs(t *testing.T) {
+	t.Parallel()
+
+	const config = `
+		dependency "vpc" {
+		  config_path = "../vpc"
+		  mock_outputs = {
+		    vpc_id = "mock"
+		  }
+		}
+
+		inputs = {
+		  vpc_id = dependency.vpc.outputs.vpc_id
+		}
+	`
+
+	tests := []struct {
+		name     string
+		command  string
+		output   string
+		ignore   bool
+		expected map[string]interface{}
+		err      string
+	}{
+		{"Applied", "apply", `{"vpc_id": {"sensitive": false, "type": "string", "value": "vpc-1"}}`, false, map[string]interface{}{"vpc_id": "vpc-1"}, ""},
+		{"Not applied plan", "plan", `{}`, false, map[string]interface{}{"vpc_id": "mock"}, ""},
+		{"Not applied apply", "apply", "", false, nil, "Unable to read the outputs of dependency vpc"},
+		{"Ignored", "apply", "", true, map[string]interface{}{"vpc_id": "mock"}, ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, "app", DefaultConfigName)
+			terragruntOptions := options.NewTerragruntOptionsForTest(configPath)
+			terragruntOptions.TerraformCliArgs = []string{tt.command}
+			terragruntOptions.IgnoreDependencyOutputs = tt.ignore
+			terragruntOptions.RunTerragrunt = func(dependencyOptions *options.TerragruntOptions) error {
+				assert.Equal(t, filepath.Join(tmpDir, "vpc", DefaultConfigName), dependencyOptions.TerragruntConfigPath)
+				assert.Equal(t, []string{"output", "-json"}, dependencyOptions.TerraformCliArgs)
+				_, err := fmt.Fprintln(dependencyOptions.Writer, tt.output)
+				return err
+			}
+
+			terragruntConfig, err := parseConfigString(config, terragruntOptions, IncludeConfig{Path: configPath})
+			if tt.err != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, terragruntConfig.Inputs)
+			assert.Equal(t, []string{filepath.Join(tmpDir, "vpc")}, terragruntConfig.Dependencies.Paths)
+			assert.Equal(t, tt.expected, terragruntOptions.DependencyOutputs["vpc"].(map[string]interface{})["outputs"])
+		})
+	}
+}
+
+func TestDependencyOutputsUnknownWithoutMocks(t *testing.T) {
+	t.Parallel()
+
+	const config = `
+		dependency "vpc" {
+		  config_path = "../vpc"
+		}
+
+		inputs = {
+		  vpc_id = dependency.vpc.outputs.vpc_id
+		}
+	`
+
+	configPath := filepath.Join(t.TempDir(), "app", DefaultConfigName)
+	terragruntOptions := options.NewTerragruntOptionsForTest(configPath)
+	terragruntOptions.IgnoreDependencyOutputs = true
+	terragruntOptions.RunTerragrunt = func(*options.TerragruntOptions) error {
+		assert.Fail(t, "The outputs should not be read")
+		return nil
+	}
+
+	terragruntConfig, err := parseConfigString(config, terragruntOptions, IncludeConfig{Path: configPath})
+	assert.NoError(t, err)
+	assert.Nil(t, terragruntConfig.Inputs)
+	assert.Len(t, terragruntConfig.Dependencies.Paths, 1)
+}
+
+func TestDependencyOutputsCache(t *testing.T) {
+	t.Parallel()
+
+	const config = `
+		dependency "vpc" {
+		  config_path = "../vpc"
+		}
+
+		inputs = {
+		  vpc_id = dependency.vpc.outputs.vpc_id
+		}
+	`
+
+	tmpDir := t.TempDir()
+	runs := 0
+	newOptions := func(name string) *options.TerragruntOptions {
+		terragruntOptions := options.NewTerragruntOptionsForTest(filepath.Join(tmpDir, name, DefaultConfigName))
+		terragruntOptions.TerraformCliArgs = []string{"apply"}
+		terragruntOptions.RunTerragrunt = func(dependencyOptions *options.TerragruntOptions) error {
+			runs++
+			_, err := fmt.Fprintln(dependencyOptions.Writer, `{"vpc_id": {"sensitive": false, "type": "string", "value": "vpc-1"}}`)
+			return err
+		}
+		return terragruntOptions
+	}
+
+	// The outputs read by the first module are reused by the second one
+	app1, app2 := newOptions("app1"), newOptions("app2")
+	app2.OutputCache = app1.OutputCache
+	for _, terragruntOptions := range []*options.TerragruntOptions{app1, app2} {
+		terragruntConfig, err := parseConfigString(config, terragruntOptions, IncludeConfig{Path: terragruntOptions.TerragruntConfigPath})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"vpc_id": "vpc-1"}, terragruntConfig.Inputs)
+	}
+	assert.Equal(t, 1, runs)
+
+	entry, found := app1.OutputCache.Get(filepath.Join(tmpDir, "vpc"))
+	assert.True(t, found)
+	assert.Equal(t, int64(0), entry.Serial)
+}
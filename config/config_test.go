@@ -173,6 +173,26 @@ func TestParseTerragruntConfigDependenciesOnePath(t *testing.T) {
 	}
 }
 
+func TestParseTerragruntConfigConcurrencyGroup(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		concurrency_group = "heavy"
+		concurrency_limits = {
+			heavy = 2
+			light = 20
+		}
+	`
+
+	terragruntConfig, err := parseConfigString(config, mockOptions, mockDefaultInclude)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "heavy", terragruntConfig.ConcurrencyGroup)
+	assert.Equal(t, map[string]int{"heavy": 2, "light": 20}, terragruntConfig.ConcurrencyLimits)
+}
+
 func TestParseTerragruntConfigDependenciesMultiplePaths(t *testing.T) {
 	t.Parallel()
 
@@ -507,6 +527,7 @@ func TestMergeConfigIntoIncludedConfig(t *testing.T) {
 	t.Parallel()
 
 	options := options.NewTerragruntOptionsForTest("TestMergeConfigIntoIncludedConfig")
+	protect, unprotect := true, false
 
 	testCases := []struct {
 		config         TerragruntConfig
@@ -538,6 +559,21 @@ func TestMergeConfigIntoIncludedConfig(t *testing.T) {
 			TerragruntConfig{RemoteState: &remote.State{Backend: "bar"}, Terraform: &TerraformConfig{Source: "bar"}},
 			TerragruntConfig{RemoteState: &remote.State{Backend: "bar"}, Terraform: &TerraformConfig{Source: "foo"}},
 		},
+		{
+			TerragruntConfig{ConcurrencyLimits: map[string]int{"heavy": 1}},
+			TerragruntConfig{ConcurrencyGroup: "heavy", ConcurrencyLimits: map[string]int{"heavy": 2, "light": 20}},
+			TerragruntConfig{ConcurrencyGroup: "heavy", ConcurrencyLimits: map[string]int{"heavy": 1, "light": 20}},
+		},
+		{
+			TerragruntConfig{},
+			TerragruntConfig{PreventDestroy: &protect},
+			TerragruntConfig{PreventDestroy: &protect},
+		},
+		{
+			TerragruntConfig{PreventDestroy: &unprotect},
+			TerragruntConfig{PreventDestroy: &protect},
+			TerragruntConfig{PreventDestroy: &unprotect},
+		},
 		{
 			getExtraArgsConfig(options, argConfig{name: "childArgs"}),
 			getExtraArgsConfig(options),
@@ -561,6 +597,18 @@ func TestMergeConfigIntoIncludedConfig(t *testing.T) {
 	}
 }
 
+func TestParseTerragruntConfigPreventDestroy(t *testing.T) {
+	t.Parallel()
+
+	terragruntConfig, err := parseConfigString("prevent_destroy = true", mockOptions, mockDefaultInclude)
+	assert.NoError(t, err)
+	assert.True(t, terragruntConfig.IsProtected())
+
+	terragruntConfig, err = parseConfigString("", mockOptions, mockDefaultInclude)
+	assert.NoError(t, err)
+	assert.False(t, terragruntConfig.IsProtected())
+}
+
 func TestParseTerragruntConfigTerraformNoSource(t *testing.T) {
 	t.Parallel()
 
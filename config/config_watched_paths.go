@@ -0,0 +1,66 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// WatchedPaths returns the list of local files, folders and glob patterns that could alter the behavior of the
+// configuration: the configuration files (including included and boot files), the local sources used by
+// import_files and import_variables and the local terraform source.
+func (conf TerragruntConfig) WatchedPaths(modulePath string) []string {
+	result := make([]string, 0, len(conf.ConfigFiles))
+	add := func(path, folder string) {
+		if path == "" || isRemoteSource(path) {
+			return
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(folder, path)
+		}
+		if path = filepath.Clean(path); !util.ListContainsElement(result, path) {
+			result = append(result, path)
+		}
+	}
+
+	for _, file := range conf.ConfigFiles {
+		add(file, modulePath)
+	}
+
+	for _, item := range conf.ImportFiles {
+		folder := item.definitionFolder(modulePath)
+		if item.Source != "" {
+			add(item.Source, folder)
+			continue
+		}
+		for _, file := range item.Files {
+			add(file, folder)
+		}
+		for _, file := range item.CopyAndRename {
+			add(file.Source, folder)
+		}
+	}
+
+	for _, item := range conf.ImportVariables {
+		folder := item.definitionFolder(modulePath)
+		for _, source := range item.Sources {
+			add(source, folder)
+		}
+		if len(item.Sources) == 0 {
+			for _, file := range append(item.RequiredVarFiles, item.OptionalVarFiles...) {
+				add(file, folder)
+			}
+		}
+	}
+
+	if conf.Terraform != nil {
+		add(conf.Terraform.Source, modulePath)
+	}
+	return result
+}
+
+// Returns true if the source is not a local path (i.e. git::, s3::, https://, etc.)
+func isRemoteSource(source string) bool {
+	return strings.Contains(source, "::") || strings.Contains(source, "://") || strings.HasPrefix(source, "git@")
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchedPaths(t *testing.T) {
+	t.Parallel()
+
+	conf := TerragruntConfig{
+		ConfigFiles: []string{"/stack/module/terragrunt.hcl", "/stack/terragrunt.hcl"},
+		Terraform:   &TerraformConfig{Source: "../modules/network"},
+		ImportFiles: ImportFilesList{
+			{Source: "git::https://github.com/org/repo.git"},
+			{Files: []string{"../common/*.tf"}, CopyAndRename: []copyAndRename{{Source: "/shared/provider.tf"}}},
+		},
+		ImportVariables: ImportVariablesList{
+			{RequiredVarFiles: []string{"vars/required.tfvars"}, OptionalVarFiles: []string{"vars/optional.tfvars"}},
+			{Sources: []string{"s3::https://bucket/vars", "../vars"}, RequiredVarFiles: []string{"ignored.tfvars"}},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"/stack/module/terragrunt.hcl",
+		"/stack/terragrunt.hcl",
+		"/stack/common/*.tf",
+		"/shared/provider.tf",
+		"/stack/module/vars/required.tfvars",
+		"/stack/module/vars/optional.tfvars",
+		"/stack/vars",
+		"/stack/modules/network",
+	}, conf.WatchedPaths("/stack/module"))
+}
@@ -4,11 +4,14 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/coveooss/multilogger"
 	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
 	"github.com/coveooss/terragrunt/v2/util"
 	"github.com/fatih/color"
 )
@@ -69,6 +72,26 @@ func (base TerragruntExtensionBase) config() *TerragruntConfigFile {
 	panic(fmt.Sprintf("No config associated with object %v", base.id()))
 }
 
+// Returns the duration specified in a timeout attribute (0 if there is no timeout)
+func (base TerragruntExtensionBase) parseTimeout(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(timeout)
+	if err != nil || duration < 0 {
+		return 0, tgerrors.WithStackTrace(invalidTimeout{base.id(), timeout})
+	}
+	return duration, nil
+}
+
+// Returns the folder of the configuration file where the item has been defined
+func (base TerragruntExtensionBase) definitionFolder(defaultFolder string) string {
+	if base._config != nil && base._config.Path != "" {
+		return filepath.Dir(base._config.Path)
+	}
+	return defaultFolder
+}
+
 // Returns the current options set associated with the object
 func (base TerragruntExtensionBase) options() *options.TerragruntOptions {
 	if options := base.config().options; options != nil {
@@ -113,3 +136,12 @@ func (errors errorArray) Error() string {
 	}
 	return strings.Join(errorsStr, "\n")
 }
+
+type invalidTimeout struct {
+	Item    string
+	Timeout string
+}
+
+func (err invalidTimeout) Error() string {
+	return fmt.Sprintf("invalid timeout '%s' in %s, it must be expressed with unit (i.e. 90s, 10m, 1h30m)", err.Timeout, err.Item)
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/shell"
+	"github.com/coveooss/terragrunt/v2/util"
+	"github.com/sirupsen/logrus"
+)
+
+// EventHook is a definition of user command or webhook that is notified on the events of the stack executions
+type EventHook struct {
+	TerragruntExtensionBase `hcl:",remain"`
+
+	Events    []string          `hcl:"events,optional"` // If not specified, the hook is notified on all events
+	Command   string            `hcl:"command,optional"`
+	Arguments []string          `hcl:"arguments,optional"`
+	EnvVars   map[string]string `hcl:"env_vars,optional"`
+	Webhook   string            `hcl:"webhook,optional"`
+	Headers   map[string]string `hcl:"headers,optional"`
+	Timeout   string            `hcl:"timeout,optional"` // Maximum duration of the command or the webhook call (i.e. 30s, 5m)
+}
+
+func (hook EventHook) itemType() (result string) { return EventHookList{}.argName() }
+
+func (hook EventHook) help() (result string) {
+	if hook.Description != "" {
+		result += fmt.Sprintf("\n%s\n", hook.Description)
+	}
+	events := hook.Events
+	if len(events) == 0 {
+		events = options.EventTypes
+	}
+	result += fmt.Sprintf("\nNotified on the following event(s): %s\n", strings.Join(events, ", "))
+	if hook.Command != "" {
+		result += fmt.Sprintf("\nCommand: %s %s\n", hook.Command, strings.Join(hook.Arguments, " "))
+	}
+	if hook.Webhook != "" {
+		result += fmt.Sprintf("\nWebhook: %s\n", hook.Webhook)
+	}
+	if hook.OS != nil {
+		result += fmt.Sprintf("\nApplied only on the following OS: %s\n", strings.Join(hook.OS, ", "))
+	}
+	return
+}
+
+func (hook *EventHook) normalize() {
+	hook.Command = strings.TrimSpace(hook.Command)
+}
+
+// DefinitionKey identifies the definition of the hook. The hooks defined in a common file that is included by several
+// modules have the same key while the hooks with the same name defined in different files have distinct keys.
+func (hook EventHook) DefinitionKey() string {
+	if hook._config != nil && hook._config.Path != "" {
+		return fmt.Sprintf("%s:%s", hook._config.Path, hook.Name)
+	}
+	return hook.Name
+}
+
+// Returns true if the hook must be notified of the event
+func (hook *EventHook) accept(event options.Event) bool {
+	return hook.enabled() && (len(hook.Events) == 0 || util.ListContainsElement(hook.Events, event.Type))
+}
+
+// Runs the command with the JSON payload on its standard input and posts the event to the webhook
+func (hook *EventHook) notify(event options.Event, payload []byte) error {
+	timeout, err := hook.parseTimeout(hook.Timeout)
+	if err != nil {
+		return err
+	}
+
+	if hook.Command != "" {
+		hookOptions := *hook.options().WithLogFields(logrus.Fields{options.LogFieldHook: hook.id()})
+		// The event hooks are not part of the module execution, so they are not subject to the module timeout
+		hookOptions.Deadline = time.Time{}
+
+		cmd := shell.NewCmd(&hookOptions, hook.Command).Args(hook.Arguments...).WithTimeout(timeout)
+		cmd.Stdin = bytes.NewReader(payload)
+		cmd.Env(fmt.Sprintf("%s=%s", options.EnvEvent, event.Type))
+		for key, value := range hook.EnvVars {
+			cmd.Env(fmt.Sprintf("%s=%s", key, value))
+		}
+		hook.logger().Debugf("Running %s (%s) on %s", hook.itemType(), hook.id(), event.Type)
+		if err := cmd.LogOutput(logrus.InfoLevel); err != nil {
+			return err
+		}
+	}
+
+	if hook.Webhook != "" {
+		return util.PostJSON(hook.Webhook, hook.Headers, event, timeout)
+	}
+	return nil
+}
+
+// ----------------------- EventHookList -----------------------
+
+//go:generate genny -in=extension_base_list.go -out=generated_event_hooks.go gen "GenericItem=EventHook"
+func (list EventHookList) argName() string { return "on_event" }
+
+func (list EventHookList) sort() EventHookList { return list }
+
+// Merge elements from an imported list to the current list
+func (list *EventHookList) Merge(imported EventHookList) {
+	list.merge(imported, mergeModeAppend, list.argName())
+}
+
+// Notify sends the event to all hooks that are interested in it. The errors are logged as warnings and do not
+// interrupt the execution.
+func (list EventHookList) Notify(event options.Event) {
+	if len(list) == 0 {
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		IEventHook(&list[0]).logger().Warningf("Unable to encode the event %s: %v", event.Type, err)
+		return
+	}
+	for i := range list {
+		hook := &list[i]
+		if !hook.accept(event) {
+			continue
+		}
+		hook.normalize()
+		if err := hook.notify(event, payload); err != nil {
+			hook.logger().Warningf("Error while notifying %s (%s) of %s: %v", hook.itemType(), hook.id(), event.Type, err)
+		}
+	}
+}
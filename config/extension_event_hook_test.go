@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventHookNotify(t *testing.T) {
+	t.Parallel()
+
+	var received []options.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event options.Event
+		content, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(content, &event))
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+		received = append(received, event)
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	outFile := filepath.Join(tmpDir, "events.json")
+	config := fmt.Sprintf(`
+		on_event "log" {
+		  events    = ["module-finished"]
+		  command   = "sh"
+		  arguments = ["-c", "cat >> %s; echo >> %s"]
+		}
+
+		on_event "slack" {
+		  webhook = "%s"
+		  headers = { X-Token = "secret" }
+		}
+	`, outFile, outFile, server.URL)
+
+	terragruntConfig, err := parseConfigString(config, options.NewTerragruntOptionsForTest(filepath.Join(tmpDir, DefaultConfigName)), mockDefaultInclude)
+	assert.NoError(t, err)
+	assert.Len(t, terragruntConfig.EventHooks, 2)
+	assert.Equal(t, []string{"module-finished"}, terragruntConfig.EventHooks[0].Events)
+
+	terragruntConfig.EventHooks.Notify(options.Event{Type: options.EventModuleStarted, Path: "a"})
+	terragruntConfig.EventHooks.Notify(options.Event{Type: options.EventModuleFinished, Path: "a", Status: "succeeded"})
+
+	assert.Len(t, received, 2)
+	assert.Equal(t, options.EventModuleStarted, received[0].Type)
+	assert.Equal(t, options.EventModuleFinished, received[1].Type)
+
+	content, err := os.ReadFile(outFile)
+	assert.NoError(t, err)
+	var event options.Event
+	assert.NoError(t, json.Unmarshal(content, &event))
+	assert.Equal(t, options.EventModuleFinished, event.Type)
+	assert.Equal(t, "succeeded", event.Status)
+}
+
+func TestEventHookDefinitionKey(t *testing.T) {
+	t.Parallel()
+
+	common := &TerragruntConfigFile{Path: "/stack/common.hcl"}
+	newHook := func(name string, config *TerragruntConfigFile) EventHook {
+		hook := EventHook{TerragruntExtensionBase: TerragruntExtensionBase{Name: name}}
+		hook.init(config)
+		return hook
+	}
+
+	assert.Equal(t, newHook("notify", common).DefinitionKey(), newHook("notify", common).DefinitionKey(), "Same hook included by several modules")
+	assert.NotEqual(t, newHook("notify", common).DefinitionKey(), newHook("other", common).DefinitionKey())
+	assert.NotEqual(t,
+		newHook("notify", &TerragruntConfigFile{Path: "/stack/a/terragrunt.hcl"}).DefinitionKey(),
+		newHook("notify", &TerragruntConfigFile{Path: "/stack/b/terragrunt.hcl"}).DefinitionKey(),
+		"Hooks with the same name defined in different modules")
+	assert.Equal(t, "notify", EventHook{TerragruntExtensionBase: TerragruntExtensionBase{Name: "notify"}}.DefinitionKey())
+}
@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/coveooss/gotemplate/v3/collections"
 	"github.com/coveooss/gotemplate/v3/utils"
@@ -31,6 +32,7 @@ type ExtraCommand struct {
 	ShellCommand bool              `hcl:"shell_command,optional"` // This indicates that the command is a shell command and output should not be redirected
 	IgnoreError  bool              `hcl:"ignore_error,optional"`
 	EnvVars      map[string]string `hcl:"env_vars,optional"`
+	Timeout      string            `hcl:"timeout,optional"` // Maximum duration of the command (i.e. 30s, 5m)
 }
 
 func (item ExtraCommand) itemType() (result string) { return ExtraCommandList{}.argName() }
@@ -55,6 +57,11 @@ func (item ExtraCommand) help() (result string) {
 	return result
 }
 
+// GetTimeout returns the maximum duration of the command (0 if there is no timeout)
+func (item ExtraCommand) GetTimeout() (time.Duration, error) {
+	return item.parseTimeout(item.Timeout)
+}
+
 func (item *ExtraCommand) normalize() {
 	if item.Commands == nil {
 		// There is no list of commands, so we consider the name to be the extra command
@@ -5,6 +5,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -14,6 +15,8 @@ import (
 	"github.com/coveooss/terragrunt/v2/shell"
 	"github.com/coveooss/terragrunt/v2/tgerrors"
 	"github.com/coveooss/terragrunt/v2/util"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type HookType int
@@ -54,6 +57,7 @@ type Hook struct {
 	ShellCommand      bool              `hcl:"shell_command,optional"` // This indicates that the command is a shell command and output should not be redirected
 	EnvVars           map[string]string `hcl:"env_vars,optional"`
 	PersistentEnvVars map[string]string `hcl:"persistent_env_vars,optional"`
+	Timeout           string            `hcl:"timeout,optional"` // Maximum duration of the hook (i.e. 30s, 5m)
 }
 
 func (hook Hook) itemType() (result string) { return HookList{}.argName() }
@@ -74,12 +78,16 @@ func (hook Hook) help() (result string) {
 		fmt.Sprintf("Expand arguments = %v", hook.ExpandArgs),
 		fmt.Sprintf("Ignore error = %v", hook.IgnoreError),
 	}
+	if hook.Timeout != "" {
+		attributes = append(attributes, fmt.Sprintf("Timeout = %s", hook.Timeout))
+	}
 	result += fmt.Sprintf("\n%s\n", strings.Join(attributes, ", "))
 	return
 }
 
 func (hook *Hook) run(...interface{}) (result []interface{}, err error) {
-	logger := hook.logger()
+	hookOptions := hook.options().WithLogFields(logrus.Fields{options.LogFieldHook: hook.id()})
+	logger := hookOptions.Logger
 
 	if len(hook.OnCommands) > 0 && !util.ListContainsElement(hook.OnCommands, hook.options().Env[options.EnvCommand]) {
 		// The current command is not in the list of command on which the hook should be applied
@@ -95,7 +103,12 @@ func (hook *Hook) run(...interface{}) (result []interface{}, err error) {
 
 	startTime := time.Now()
 	defer func() {
-		logger.Debugf("Hook timings: %s %s ran in %s", hook.Type, hook.id(), time.Since(startTime))
+		duration := time.Since(startTime)
+		logger.Debugf("Hook timings: %s %s ran in %s", hook.Type, hook.id(), duration)
+		if hook.Command != "" {
+			modulePath := filepath.Dir(hook.options().TerragruntConfigPath)
+			util.RecordHookTiming(modulePath, hook.options().Env[options.EnvCommand], fmt.Sprintf("%s %s", hook.Type, hook.id()), duration)
+		}
 	}()
 
 	hook.Command = strings.TrimSpace(hook.Command)
@@ -110,7 +123,11 @@ func (hook *Hook) run(...interface{}) (result []interface{}, err error) {
 		hook.options().Env[key] = value
 	}
 
-	cmd := shell.NewCmd(hook.options(), hook.Command).Args(hook.Arguments...)
+	endSpan := hookOptions.StartSpan(fmt.Sprintf("%s %s", hook.Type, hook.id()), attribute.String("terragrunt.hook.command", hook.Command))
+	defer func() { endSpan(err) }()
+
+	cmd := shell.NewCmd(hookOptions, hook.Command).Args(hook.Arguments...)
+	defer hookOptions.FlushOutput()
 
 	// Add local environment variables to the current context
 	// (these variables will be available only while execution of the hook)
@@ -133,7 +150,12 @@ func (hook *Hook) run(...interface{}) (result []interface{}, err error) {
 	if shouldBeApproved, approvalConfig := hook.config().ApprovalConfig.ShouldBeApproved(hook.Command); shouldBeApproved {
 		cmd = cmd.Expect(approvalConfig.ExpectStatements, approvalConfig.CompletedStatements)
 	}
-	err = cmd.Run()
+
+	timeout, err := hook.parseTimeout(hook.Timeout)
+	if err != nil {
+		return
+	}
+	err = cmd.WithTimeout(timeout).Run()
 	return
 }
 
@@ -2,7 +2,9 @@ package config
 
 import (
 	"testing"
+	"time"
 
+	"github.com/coveooss/terragrunt/v2/tgerrors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -24,3 +26,26 @@ func TestHookTypeStringConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestHookTimeout(t *testing.T) {
+	testCases := []struct {
+		timeout     string
+		expected    time.Duration
+		expectedErr error
+	}{
+		{"", 0, nil},
+		{"90s", 90 * time.Second, nil},
+		{"1h30m", 90 * time.Minute, nil},
+		{"10", 0, invalidTimeout{"hook", "10"}},
+		{"-5m", 0, invalidTimeout{"hook", "-5m"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.timeout, func(t *testing.T) {
+			hook := Hook{TerragruntExtensionBase: TerragruntExtensionBase{Name: "hook"}, Timeout: testCase.timeout}
+			actual, err := hook.parseTimeout(hook.Timeout)
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.expectedErr, tgerrors.Unwrap(err))
+		})
+	}
+}
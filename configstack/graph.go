@@ -1,6 +1,9 @@
 package configstack
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/coveooss/terragrunt/v2/tgerrors"
 	"github.com/coveooss/terragrunt/v2/util"
 )
@@ -49,3 +52,73 @@ func checkForCyclesUsingDepthFirstSearch(module *TerraformModule, visitedPaths *
 
 	return nil
 }
+
+// Dot renders the dependency graph of the modules in Graphviz dot format. The edges go from the dependency to the
+// dependent module (in deployment order). External modules are dashed and modules ignored by their run conditions
+// are grayed out.
+func (modules SimpleTerraformModules) Dot() string {
+	var builder strings.Builder
+	builder.WriteString("digraph stack {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, node := range modules.graphNodes() {
+		attributes := []string{fmt.Sprintf("label=%q", node.Path)}
+		if node.External {
+			attributes = append(attributes, `style=dashed`)
+		}
+		if node.Ignored {
+			attributes = append(attributes, `color=gray`, `fontcolor=gray`)
+		}
+		fmt.Fprintf(&builder, "  %q [%s];\n", node.Path, strings.Join(attributes, ", "))
+	}
+	for _, module := range modules {
+		for _, dependency := range module.Dependencies {
+			fmt.Fprintf(&builder, "  %q -> %q;\n", dependency, module.Path)
+		}
+	}
+	builder.WriteString("}")
+	return builder.String()
+}
+
+// Mermaid renders the dependency graph of the modules as a Mermaid flowchart (see Dot for the conventions used)
+func (modules SimpleTerraformModules) Mermaid() string {
+	var builder strings.Builder
+	builder.WriteString("flowchart LR\n")
+	nodes := modules.graphNodes()
+	ids := make(map[string]string, len(nodes))
+	var external, ignored []string
+	for i, node := range nodes {
+		ids[node.Path] = fmt.Sprintf("m%d", i)
+		fmt.Fprintf(&builder, "  %s[%q]\n", ids[node.Path], node.Path)
+		if node.External {
+			external = append(external, ids[node.Path])
+		}
+		if node.Ignored {
+			ignored = append(ignored, ids[node.Path])
+		}
+	}
+	for _, module := range modules {
+		for _, dependency := range module.Dependencies {
+			fmt.Fprintf(&builder, "  %s --> %s\n", ids[dependency], ids[module.Path])
+		}
+	}
+	if len(external) > 0 {
+		fmt.Fprintf(&builder, "  classDef external stroke-dasharray: 5 5\n  class %s external\n", strings.Join(external, ","))
+	}
+	if len(ignored) > 0 {
+		fmt.Fprintf(&builder, "  classDef ignored fill:#eee,color:#999\n  class %s ignored\n", strings.Join(ignored, ","))
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// Returns the nodes of the graph, including the dependencies that are not part of the list of modules (i.e. when
+// the list has been filtered)
+func (modules SimpleTerraformModules) graphNodes() SimpleTerraformModules {
+	nodes := append(SimpleTerraformModules{}, modules...)
+	for _, module := range modules {
+		for _, dependency := range module.Dependencies {
+			if nodes.find(dependency) == nil {
+				nodes = append(nodes, SimpleTerraformModule{Path: dependency})
+			}
+		}
+	}
+	return nodes
+}
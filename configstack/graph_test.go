@@ -85,3 +85,35 @@ func TestCheckForCycles(t *testing.T) {
 		}
 	}
 }
+
+func TestGraphRendering(t *testing.T) {
+	t.Parallel()
+
+	modules := SimpleTerraformModules{
+		{Path: "network", External: true},
+		{Path: "app", Dependencies: []string{"network"}},
+		{Path: "dns", Ignored: true},
+	}
+
+	assert.Equal(t, `digraph stack {
+  rankdir=LR;
+  node [shape=box];
+  "network" [label="network", style=dashed];
+  "app" [label="app"];
+  "dns" [label="dns", color=gray, fontcolor=gray];
+  "network" -> "app";
+}`, modules.Dot())
+
+	assert.Equal(t, `flowchart LR
+  m0["network"]
+  m1["app"]
+  m2["dns"]
+  m0 --> m1
+  classDef external stroke-dasharray: 5 5
+  class m0 external
+  classDef ignored fill:#eee,color:#999
+  class m2 ignored`, modules.Mermaid())
+
+	// Dependencies that are not in the list are still rendered
+	assert.Contains(t, modules[1:2].Mermaid(), `m1["network"]`)
+}
@@ -44,13 +44,20 @@ func (module *TerraformModule) Simple() SimpleTerraformModule {
 	for _, dependency := range module.Dependencies {
 		dependencies = append(dependencies, dependency.Path)
 	}
-	return SimpleTerraformModule{module.Path, dependencies}
+	return SimpleTerraformModule{
+		Path:         module.Path,
+		Dependencies: dependencies,
+		External:     module.AssumeAlreadyApplied,
+		Ignored:      !module.Config.RunConditions.IsSatisfied(),
+	}
 }
 
 // SimpleTerraformModule represents a simplified version of TerraformModule
 type SimpleTerraformModule struct {
 	Path         string   `json:"path"`
 	Dependencies []string `json:"dependencies,omitempty" yaml:",omitempty"`
+	External     bool     `json:"external,omitempty" yaml:",omitempty"`
+	Ignored      bool     `json:"ignored,omitempty" yaml:",omitempty"`
 }
 
 // SimpleTerraformModules represents a list of simplified version of TerraformModule
@@ -60,6 +67,7 @@ type SimpleTerraformModules []SimpleTerraformModule
 func (modules SimpleTerraformModules) MakeRelative() (result SimpleTerraformModules) {
 	result = make(SimpleTerraformModules, len(modules))
 	for i := range modules {
+		result[i] = modules[i]
 		result[i].Path = util.GetPathRelativeToWorkingDir(modules[i].Path)
 		result[i].Dependencies = make([]string, len(modules[i].Dependencies))
 		for j := range result[i].Dependencies {
@@ -69,6 +77,73 @@ func (modules SimpleTerraformModules) MakeRelative() (result SimpleTerraformModu
 	return
 }
 
+// DependenciesOf returns the modules on which the module at the specified path depends (directly or transitively)
+func (modules SimpleTerraformModules) DependenciesOf(path string, transitive bool) (SimpleTerraformModules, error) {
+	target := modules.find(path)
+	if target == nil {
+		return nil, tgerrors.WithStackTrace(errModuleNotFound(path))
+	}
+
+	selected := make(map[string]bool)
+	var add func(module *SimpleTerraformModule)
+	add = func(module *SimpleTerraformModule) {
+		for _, dependency := range module.Dependencies {
+			if !selected[dependency] {
+				selected[dependency] = true
+				if next := modules.find(dependency); next != nil && transitive {
+					add(next)
+				}
+			}
+		}
+	}
+	add(target)
+	return modules.filter(selected), nil
+}
+
+// DependentsOf returns the modules that depend (directly or transitively) on the module at the specified path
+func (modules SimpleTerraformModules) DependentsOf(path string, transitive bool) (SimpleTerraformModules, error) {
+	target := modules.find(path)
+	if target == nil {
+		return nil, tgerrors.WithStackTrace(errModuleNotFound(path))
+	}
+
+	selected := make(map[string]bool)
+	var add func(path string)
+	add = func(path string) {
+		for _, module := range modules {
+			if !selected[module.Path] && util.ListContainsElement(module.Dependencies, path) {
+				selected[module.Path] = true
+				if transitive {
+					add(module.Path)
+				}
+			}
+		}
+	}
+	add(target.Path)
+	return modules.filter(selected), nil
+}
+
+// Returns the module with the specified path (or nil if it is not in the list)
+func (modules SimpleTerraformModules) find(path string) *SimpleTerraformModule {
+	for i := range modules {
+		if modules[i].Path == path {
+			return &modules[i]
+		}
+	}
+	return nil
+}
+
+// Returns the modules selected, preserving the original order
+func (modules SimpleTerraformModules) filter(selected map[string]bool) SimpleTerraformModules {
+	result := make(SimpleTerraformModules, 0, len(selected))
+	for _, module := range modules {
+		if selected[module.Path] {
+			result = append(result, module)
+		}
+	}
+	return result
+}
+
 // ResolveTerraformModules goes through each of the given Terragrunt configuration files and resolve the module that configuration file represents
 // into a TerraformModule struct. Return the list of these TerraformModule structures.
 func ResolveTerraformModules(terragruntConfigPaths []string, terragruntOptions *options.TerragruntOptions) ([]*TerraformModule, error) {
@@ -134,7 +209,11 @@ func resolveTerraformModule(terragruntConfigPath string, terragruntOptions *opti
 	}
 
 	opts := terragruntOptions.Clone(terragruntConfigPath)
+	// The outputs of the dependencies are not required to resolve the stack (and they may not be applied yet), they are
+	// read when the module is actually run
+	opts.IgnoreDependencyOutputs = true
 	_, terragruntConfig, err := config.ParseConfigFile(opts, config.IncludeConfig{Path: terragruntConfigPath})
+	opts.IgnoreDependencyOutputs, opts.DependencyOutputs = false, nil
 	if err != nil {
 		return
 	}
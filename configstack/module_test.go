@@ -331,3 +331,47 @@ func Abs(path string) string {
 	absPath, _ := filepath.Abs(path)
 	return absPath
 }
+
+func TestSimpleTerraformModulesDependencies(t *testing.T) {
+	t.Parallel()
+
+	modules := createFilterTestStack().SimpleModules()
+	paths := func(modules SimpleTerraformModules) (result []string) {
+		for _, module := range modules {
+			result = append(result, module.Path)
+		}
+		return
+	}
+
+	testCases := []struct {
+		name        string
+		dependents  bool
+		path        string
+		transitive  bool
+		expected    []string
+		expectedErr error
+	}{
+		{name: "direct dependencies", path: "/stack/app/frontend", expected: []string{"/stack/app/backend"}},
+		{name: "transitive dependencies", path: "/stack/app/frontend", transitive: true, expected: []string{"/stack/network", "/stack/app/backend"}},
+		{name: "no dependency", path: "/stack/dns", expected: nil},
+		{name: "direct dependents", dependents: true, path: "/stack/network", expected: []string{"/stack/app/backend"}},
+		{name: "transitive dependents", dependents: true, path: "/stack/network", transitive: true, expected: []string{"/stack/app/backend", "/stack/app/frontend"}},
+		{name: "unknown module", dependents: true, path: "/stack/unknown", expectedErr: errModuleNotFound("/stack/unknown")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			query := modules.DependenciesOf
+			if testCase.dependents {
+				query = modules.DependentsOf
+			}
+			result, err := query(testCase.path, testCase.transitive)
+			if testCase.expectedErr != nil {
+				assert.Equal(t, testCase.expectedErr, tgerrors.Unwrap(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, paths(result))
+		})
+	}
+}
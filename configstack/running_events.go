@@ -0,0 +1,148 @@
+package configstack
+
+import (
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/config"
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// eventNotifier sends the lifecycle events of a stack execution to the listeners registered on the options and to the
+// event hooks (on_event) defined in the modules configuration. The module events are only sent to the hooks of the
+// module while the stack events are sent once to every distinct hook defined in the stack. The events are queued and
+// delivered in order by a separate goroutine, so slow hooks or webhooks do not delay the modules execution. All methods
+// are nil safe, so a nil notifier can be used when there is nobody to notify.
+type eventNotifier struct {
+	listeners []options.EventListener
+	hooks     config.EventHookList
+	runID     string
+	command   string
+	path      string
+	modules   []string
+	start     time.Time
+	queue     chan func()
+	done      chan struct{}
+}
+
+// Maximum number of events waiting to be delivered, the modules wait before notifying new events when the queue is full
+const eventQueueSize = 100
+
+// Returns a new notifier for the modules (returns nil if there is no listener nor event hook)
+func newEventNotifier(modules []*TerraformModule) *eventNotifier {
+	if len(modules) == 0 {
+		return nil
+	}
+
+	terragruntOptions := modules[0].TerragruntOptions
+	notifier := &eventNotifier{
+		listeners: terragruntOptions.EventListeners,
+		runID:     terragruntOptions.Env[options.EnvRunID],
+		command:   modules[0].command(),
+		path:      terragruntOptions.Env[options.EnvLaunchFolder],
+		modules:   make([]string, 0, len(modules)),
+	}
+	keys := map[string]bool{}
+	for _, module := range modules {
+		notifier.modules = append(notifier.modules, util.GetPathRelativeToWorkingDir(module.Path))
+		for _, hook := range module.Config.EventHooks {
+			if key := hook.DefinitionKey(); !keys[key] {
+				// The hooks defined in a common file are included in every module, but they are notified only once
+				keys[key] = true
+				notifier.hooks = append(notifier.hooks, hook)
+			}
+		}
+	}
+
+	if len(notifier.listeners) == 0 && len(notifier.hooks) == 0 {
+		return nil
+	}
+	return notifier
+}
+
+func (notifier *eventNotifier) newEvent(eventType, path string) options.Event {
+	return options.Event{
+		Type:      eventType,
+		RunID:     notifier.runID,
+		Command:   notifier.command,
+		Timestamp: time.Now(),
+		Path:      path,
+	}
+}
+
+// Queues the event, it is delivered to the listeners and the hooks by the goroutine started by stackStarted
+func (notifier *eventNotifier) notify(event options.Event, hooks config.EventHookList) {
+	notifier.queue <- func() {
+		for _, listener := range notifier.listeners {
+			listener(event)
+		}
+		hooks.Notify(event)
+	}
+}
+
+func (notifier *eventNotifier) stackStarted() {
+	if notifier == nil {
+		return
+	}
+	notifier.start = time.Now()
+	notifier.queue = make(chan func(), eventQueueSize)
+	notifier.done = make(chan struct{})
+	go func() {
+		defer close(notifier.done)
+		for deliver := range notifier.queue {
+			deliver()
+		}
+	}()
+	event := notifier.newEvent(options.EventStackStarted, notifier.path)
+	event.Modules = notifier.modules
+	notifier.notify(event, notifier.hooks)
+}
+
+func (notifier *eventNotifier) moduleStarted(module *runningModule) {
+	if notifier == nil {
+		return
+	}
+	event := notifier.newEvent(options.EventModuleStarted, util.GetPathRelativeToWorkingDir(module.Module.Path))
+	event.Worker = module.workerID
+	notifier.notify(event, module.Module.Config.EventHooks)
+}
+
+func (notifier *eventNotifier) moduleFinished(module *runningModule, err error) {
+	if notifier == nil {
+		return
+	}
+	event := notifier.newEvent(options.EventModuleFinished, util.GetPathRelativeToWorkingDir(module.Module.Path))
+	event.Worker = module.workerID
+	event.Status = moduleResult{Module: *module.Module, Err: err}.status()
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if !module.startTime.IsZero() {
+		event.Duration = time.Since(module.startTime).Seconds()
+	}
+	notifier.notify(event, module.Module.Config.EventHooks)
+}
+
+func (notifier *eventNotifier) stackFinished(results []moduleResult, err error) {
+	if notifier == nil {
+		return
+	}
+	event := notifier.newEvent(options.EventStackFinished, notifier.path)
+	event.Modules = notifier.modules
+	event.Status = reportSucceeded
+	if err != nil {
+		event.Status = reportFailed
+		event.Error = err.Error()
+	}
+	for _, result := range results {
+		if result.status() == reportFailed {
+			event.Failed = append(event.Failed, util.GetPathRelativeToWorkingDir(result.Module.Path))
+		}
+	}
+	event.Duration = time.Since(notifier.start).Seconds()
+	notifier.notify(event, notifier.hooks)
+
+	// The stack execution is not completed until all the events have been delivered
+	close(notifier.queue)
+	<-notifier.done
+}
@@ -0,0 +1,45 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunModulesEvents(t *testing.T) {
+	t.Parallel()
+
+	aRan := false
+	expectedErrA := fmt.Errorf("Expected error for module a")
+	terragruntOptionsA := optionsWithMockTerragruntCommand("a", expectedErrA, &aRan)
+	moduleA := &TerraformModule{Path: "a", TerragruntOptions: terragruntOptionsA}
+
+	bRan := false
+	moduleB := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{moduleA}, TerragruntOptions: optionsWithMockTerragruntCommand("b", nil, &bRan)}
+
+	// The modules are executed one after the other since b depends on a, so the events are received in order
+	var events []options.Event
+	terragruntOptionsA.AddEventListener(func(event options.Event) { events = append(events, event) })
+
+	_, err := runModulesWithContext(context.Background(), []*TerraformModule{moduleA, moduleB}, nil, NormalOrder)
+	assert.Error(t, err)
+
+	type summary struct{ Type, Path, Status string }
+	actual := make([]summary, 0, len(events))
+	for _, event := range events {
+		actual = append(actual, summary{event.Type, event.Path, event.Status})
+	}
+	assert.Equal(t, []summary{
+		{options.EventStackStarted, "", ""},
+		{options.EventModuleStarted, "a", ""},
+		{options.EventModuleFinished, "a", reportFailed},
+		{options.EventModuleFinished, "b", reportDependencyFailed},
+		{options.EventStackFinished, "", reportFailed},
+	}, actual)
+	assert.Equal(t, []string{"a", "b"}, events[0].Modules)
+	assert.Equal(t, expectedErrA.Error(), events[2].Error)
+	assert.Equal(t, []string{"a"}, events[4].Failed)
+}
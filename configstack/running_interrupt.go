@@ -0,0 +1,45 @@
+package configstack
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/coveooss/multilogger"
+	"github.com/coveooss/terragrunt/v2/shell"
+)
+
+// Returns a context that is cancelled when the first interrupt signal is received. Once the context is cancelled, no
+// new module is started, the running modules receive the signal from the shell package and are expected to terminate
+// gracefully. The signals are handled until the returned function is called: the commands that are still running are
+// killed when another signal is received (including the commands started after the first signal).
+func newInterruptContext(logger *multilogger.Logger) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		for received := 1; ; received++ {
+			select {
+			case s := <-signals:
+				if received == 1 {
+					logger.Warningf("Signal %v received, no new module will be started (send it again to kill the running modules)", s)
+					cancel()
+				} else {
+					logger.Warningf("Signal %v received again, killing the running commands", s)
+					shell.KillRunningCommands(logger)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		close(done)
+		cancel()
+	}
+}
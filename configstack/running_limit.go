@@ -2,6 +2,7 @@ package configstack
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
@@ -30,6 +31,80 @@ func freeWorker(token int) { burstyLimiter <- token }
 
 var burstyLimiter chan int
 
+// failureLimit keeps track of the number of modules that failed during a stack execution to determine if new modules
+// should still be started (see --terragrunt-fail-fast and --terragrunt-max-failures)
+type failureLimit struct {
+	mutex    sync.Mutex
+	max      int
+	failures int
+	stop     chan struct{} // Closed once the limit is reached (created by stopped)
+}
+
+func newFailureLimit(modules []*TerraformModule) *failureLimit {
+	if len(modules) == 0 {
+		return &failureLimit{}
+	}
+	terragruntOptions := modules[0].TerragruntOptions
+	if terragruntOptions.FailFast {
+		return &failureLimit{max: 1}
+	}
+	return &failureLimit{max: terragruntOptions.MaxFailures}
+}
+
+func (limit *failureLimit) add() {
+	limit.mutex.Lock()
+	defer limit.mutex.Unlock()
+	limit.failures++
+	if limit.stop != nil && limit.max > 0 && limit.failures == limit.max {
+		close(limit.stop)
+	}
+}
+
+// Returns a channel that is closed once the limit is reached
+func (limit *failureLimit) stopped() <-chan struct{} {
+	limit.mutex.Lock()
+	defer limit.mutex.Unlock()
+	if limit.stop == nil {
+		limit.stop = make(chan struct{})
+		if limit.max > 0 && limit.failures >= limit.max {
+			close(limit.stop)
+		}
+	}
+	return limit.stop
+}
+
+func (limit *failureLimit) reached() bool {
+	limit.mutex.Lock()
+	defer limit.mutex.Unlock()
+	return limit.max > 0 && limit.failures >= limit.max
+}
+
+// concurrencyGroups contains the maximum number of modules of each concurrency group (see concurrency_group and
+// concurrency_limits in the terragrunt config) that can run simultaneously. These limits are applied in addition
+// to the global number of workers.
+type concurrencyGroups map[string]int
+
+// Returns the limits of the groups used by the modules. If a group has different limits in the module configurations,
+// the smallest one is used. Groups without limit (or with a limit <= 0) are not restricted.
+func newConcurrencyGroups(modules []*TerraformModule) concurrencyGroups {
+	groups := concurrencyGroups{}
+	for _, module := range modules {
+		group := module.Config.ConcurrencyGroup
+		if limit := module.Config.ConcurrencyLimits[group]; group != "" && limit > 0 {
+			if current, exist := groups[group]; !exist || limit < current {
+				groups[group] = limit
+			}
+		}
+	}
+	return groups
+}
+
+// Returns true if no other module of the group can be started
+func (groups concurrencyGroups) isFull(group string, running int) bool {
+	limit, restricted := groups[group]
+	return restricted && running >= limit
+}
+
 // OutputPeriodicLogs displays current module output for long running request
 func (module *runningModule) OutputPeriodicLogs(completed *bool) {
 	if module.Module.TerragruntOptions.RefreshOutputDelay == 0 {
@@ -2,16 +2,21 @@ package configstack
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"math"
+	"os"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/coveooss/terragrunt/v2/options"
 	"github.com/coveooss/terragrunt/v2/shell"
 	"github.com/coveooss/terragrunt/v2/tgerrors"
 	"github.com/coveooss/terragrunt/v2/util"
 	"github.com/fatih/color"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ModuleStatus represents the status of a module that we are trying to apply as part of the apply-all or destroy-all command
@@ -46,10 +51,19 @@ type runningModule struct {
 	OutStream      bytes.Buffer
 	Writer         io.Writer
 	Handler        ModuleHandler
-	Mutex          *sync.Mutex // A shared mutex pointer to ensure that there is no concurrency problem when job finish and report
+	Mutex          *sync.Mutex        // A shared mutex pointer to ensure that there is no concurrency problem when job finish and report
+	FailureLimit   *failureLimit      // A shared failure counter used to stop starting new modules once the limit of failures is reached
+	Scheduler      *scheduler         // The shared scheduler that assigns the workers to the modules that are ready to run
+	Progress       *progressDashboard // The shared live status of the modules (nil if --terragrunt-progress is not enabled)
+	Events         *eventNotifier     // The shared notifier of the lifecycle events (nil if there is nobody to notify)
 
 	bufferIndex int // Indicates the position of the buffer that has been flushed to the logger
 	workerID    int
+	worker      chan int        // Used by the scheduler to assign a worker to the module
+	priority    float64         // The estimated length of the longest chain of modules waiting after this one
+	lastOutput  *lastLineWriter // Keeps the last line of output displayed by the progress dashboard
+	startTime   time.Time
+	endTime     time.Time
 }
 
 func (module runningModule) displayName() string {
@@ -76,7 +90,7 @@ type ModuleHandler func(TerraformModule, string, error) (string, error)
 // Create a new RunningModule struct for the given module. This will initialize all fields to reasonable defaults,
 // except for the Dependencies and NotifyWhenDone, both of which will be empty. You should fill these using a
 // function such as crossLinkDependencies.
-func newRunningModule(module *TerraformModule, mutex *sync.Mutex) *runningModule {
+func newRunningModule(module *TerraformModule, mutex *sync.Mutex, limit *failureLimit, scheduler *scheduler) *runningModule {
 	return &runningModule{
 		Module:         module,
 		Status:         waiting,
@@ -85,6 +99,9 @@ func newRunningModule(module *TerraformModule, mutex *sync.Mutex) *runningModule
 		NotifyWhenDone: []*runningModule{},
 		Writer:         module.TerragruntOptions.Writer,
 		Mutex:          mutex,
+		FailureLimit:   limit,
+		Scheduler:      scheduler,
+		lastOutput:     &lastLineWriter{},
 	}
 }
 
@@ -108,9 +125,28 @@ func runModulesReverseOrder(modules []*TerraformModule) error {
 // This version accepts a function as parameter (see: ModuleHander). The handler is called when the command is
 // completed (either succeeded or failed).
 func runModulesWithHandler(modules []*TerraformModule, handler ModuleHandler, order dependencyOrder) error {
-	runningModules, err := toRunningModules(modules, order)
+	_, err := runModulesWithResults(modules, handler, order)
+	return err
+}
+
+// Run the given modules as runModulesWithHandler does, but also returns the individual result of each module (in the
+// same order as the supplied modules).
+func runModulesWithResults(modules []*TerraformModule, handler ModuleHandler, order dependencyOrder) ([]moduleResult, error) {
+	if len(modules) == 0 {
+		return runModulesWithContext(context.Background(), modules, handler, order)
+	}
+	ctx, cancel := newInterruptContext(modules[0].TerragruntOptions.Logger)
+	defer cancel()
+	return runModulesWithContext(ctx, modules, handler, order)
+}
+
+// Run the given modules as runModulesWithResults does. Modules that are not started yet when the context is cancelled
+// are not started at all.
+func runModulesWithContext(ctx context.Context, modules []*TerraformModule, handler ModuleHandler, order dependencyOrder) ([]moduleResult, error) {
+	scheduler := newScheduler(modules)
+	runningModules, err := toRunningModules(modules, order, scheduler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(modules) != 0 {
@@ -122,41 +158,72 @@ func runModulesWithHandler(modules []*TerraformModule, handler ModuleHandler, or
 		initWorkers(nbWorkers)
 	}
 
+	progress := newProgressDashboard(runningModules)
+	progress.run()
+	events := newEventNotifier(modules)
+	events.stackStarted()
+
 	var waitGroup sync.WaitGroup
 	for _, module := range runningModules {
 		waitGroup.Add(1)
 		module.Handler = handler
+		module.Progress = progress
+		module.Events = events
 		go func(module *runningModule) {
 			var completed bool
 			defer func() {
 				waitGroup.Done()
 				completed = true
 			}()
-			logCatcher := module.Module.TerragruntOptions.Logger.Copy().SetStdout(&module.OutStream)
+			if module.Module.TerragruntOptions.JSONLogs() {
+				// The output is logged line by line as soon as it is written, we only keep a copy for the handler
+				module.Module.TerragruntOptions.CaptureOutput(&module.OutStream)
+				module.runModuleWhenReady(ctx)
+				return
+			}
+			var stdout io.Writer = &module.OutStream
+			if progress != nil {
+				// The dashboard displays the last line of output instead of the periodic logs and it must be
+				// cleared before anything else is written to the terminal
+				stdout = io.MultiWriter(&module.OutStream, module.lastOutput)
+				module.Module.TerragruntOptions.Logger = module.Module.TerragruntOptions.Logger.Copy().SetOut(progress.wrap(os.Stderr))
+				module.Writer = progress.wrap(module.Writer)
+			} else {
+				go module.OutputPeriodicLogs(&completed) // Flush the output buffers periodically to confirm that the process is still alive
+			}
+			logCatcher := module.Module.TerragruntOptions.Logger.Copy().SetStdout(stdout)
 			module.Module.TerragruntOptions.Writer = logCatcher
 			module.Module.TerragruntOptions.ErrWriter = logCatcher
-			go module.OutputPeriodicLogs(&completed) // Flush the output buffers periodically to confirm that the process is still alive
-			module.runModuleWhenReady()
+			module.runModuleWhenReady(ctx)
 		}(module)
 	}
 
 	waitGroup.Wait()
+	progress.stop()
+	scheduler.close()
 
-	return collectErrors(runningModules)
+	results, err := collectResults(modules, runningModules), collectErrors(runningModules)
+	events.stackFinished(results, err)
+	return results, err
 }
 
 // Convert the list of modules to a map from module path to a runningModule struct. This struct contains information
 // about executing the module, such as whether it has finished running or not and any errors that happened. Note that
-// this does NOT actually run the module. For that, see the runModules method.
-func toRunningModules(modules []*TerraformModule, dependencyOrder dependencyOrder) (map[string]*runningModule, error) {
+// this does NOT actually run the module. For that, see the runModules method. The modules share the given scheduler.
+func toRunningModules(modules []*TerraformModule, dependencyOrder dependencyOrder, scheduler *scheduler) (map[string]*runningModule, error) {
 	var mutex sync.Mutex
+	limit := newFailureLimit(modules)
 
 	runningModules := map[string]*runningModule{}
 	for _, module := range modules {
-		runningModules[module.Path] = newRunningModule(module, &mutex)
+		runningModules[module.Path] = newRunningModule(module, &mutex, limit, scheduler)
 	}
 
-	return crossLinkRunningModulesDependencies(runningModules, dependencyOrder)
+	runningModules, err := crossLinkRunningModulesDependencies(runningModules, dependencyOrder)
+	if err == nil {
+		scheduler.prioritize(runningModules)
+	}
+	return runningModules, err
 }
 
 // Loop through the map of runningModules and for each module M:
@@ -200,6 +267,33 @@ func collectErrors(modules map[string]*runningModule) error {
 	return tgerrors.WithStackTrace(CreateMultiErrors(errs))
 }
 
+// Collect the results of the given modules in the same order as the original list of modules
+func collectResults(modules []*TerraformModule, runningModules map[string]*runningModule) []moduleResult {
+	results := make([]moduleResult, 0, len(modules))
+	for _, module := range modules {
+		if runningModule, found := runningModules[module.Path]; found {
+			results = append(results, runningModule.result())
+		}
+	}
+	return results
+}
+
+// Returns the result of the module execution
+func (module *runningModule) result() moduleResult {
+	exitCode, err := shell.GetExitCode(module.Err)
+	if err != nil {
+		exitCode = undefinedExitCode
+	}
+	return moduleResult{
+		Module:    *module.Module,
+		Err:       module.Err,
+		ExitCode:  exitCode,
+		WorkerID:  module.workerID,
+		StartTime: module.startTime,
+		EndTime:   module.endTime,
+	}
+}
+
 // Run a module once all of its dependencies have finished executing.
 func (module *runningModule) dependencies() []string {
 	result := make([]string, 0, len(module.Dependencies))
@@ -210,19 +304,36 @@ func (module *runningModule) dependencies() []string {
 }
 
 // Run a module once all of its dependencies have finished executing.
-func (module *runningModule) runModuleWhenReady() {
-	err := module.waitForDependencies()
+func (module *runningModule) runModuleWhenReady(ctx context.Context) {
+	err := module.waitForDependencies(ctx)
 	if err == nil {
-		module.workerID = waitWorker()
-		defer func() { freeWorker(module.workerID) }()
-		err = module.runNow()
+		// The module does not get a worker if the execution is interrupted or if the maximum number of failures is
+		// reached while it is waiting, it is then cancelled or skipped below
+		if module.Scheduler.acquire(ctx, module) {
+			defer module.Scheduler.release(module)
+			if terragruntOptions := module.Module.TerragruntOptions; terragruntOptions.JSONLogs() {
+				terragruntOptions.SetLogger(terragruntOptions.Logger.WithField(options.LogFieldWorker, module.workerID))
+			}
+		}
+		if ctx.Err() != nil {
+			module.Module.TerragruntOptions.Logger.Warningf("Module %s is cancelled", module.displayName())
+			err = errModuleCancelled{module.Module}
+		} else if module.FailureLimit.reached() {
+			module.Module.TerragruntOptions.Logger.Warningf("Module %s is skipped because the maximum number of failures has been reached", module.displayName())
+			err = errModuleSkipped{module.Module}
+		} else {
+			module.Progress.moduleStarted(module)
+			module.Events.moduleStarted(module)
+			err = module.runNow()
+		}
 	}
 	module.moduleFinished(err)
 }
 
 // Wait for all of this modules dependencies to finish executing. Return an error if any of those dependencies complete
-// with an error. Return immediately if this module has no dependencies.
-func (module *runningModule) waitForDependencies() error {
+// with an error (the module is cancelled if the error occurred after an interruption). Return immediately if this
+// module has no dependencies.
+func (module *runningModule) waitForDependencies(ctx context.Context) error {
 	log := module.Module.TerragruntOptions.Logger
 	if len(module.Dependencies) > 0 {
 		log.Debugf("Module %s must wait for %s to finish", module.displayName(), strings.Join(module.dependencies(), ", "))
@@ -233,6 +344,24 @@ func (module *runningModule) waitForDependencies() error {
 
 		depPath := util.GetPathRelativeToWorkingDirMax(doneDependency.Module.Path, 3)
 
+		// If a dependency has not been started, this module will not be started either
+		switch doneDependency.Err.(type) {
+		case errModuleSkipped:
+			return errModuleSkipped{module.Module}
+		case errModuleCancelled:
+			return errModuleCancelled{module.Module}
+		case errModuleRejected:
+			// The dependents of a rejected module are skipped, even if dependency errors are ignored
+			rejected := doneDependency.Err.(errModuleRejected).RejectedModule
+			if rejected == nil {
+				rejected = doneDependency.Module
+			}
+			return errModuleRejected{module.Module, rejected}
+		}
+		if doneDependency.Err != nil && ctx.Err() != nil {
+			// The dependency has most likely been interrupted, this module would not be started anyway
+			return errModuleCancelled{module.Module}
+		}
 		if doneDependency.Err != nil {
 			if module.Module.TerragruntOptions.IgnoreDependencyErrors {
 				log.Warningf("Dependency %[1]s of module %[2]s just finished with an error. Module %[2]s will have to return an error too. However, because of --terragrunt-ignore-dependency-errors, module %[2]s will run anyway.", depPath, module.displayName())
@@ -255,13 +384,23 @@ func (module *runningModule) waitForDependencies() error {
 // Run a module right now by executing the RunTerragrunt command of its TerragruntOptions field.
 func (module *runningModule) runNow() error {
 	module.Status = running
+	module.startTime = time.Now()
 
 	if module.Module.AssumeAlreadyApplied {
 		module.Module.TerragruntOptions.Logger.Debugf("Assuming module %s has already been applied and skipping it", module.displayName())
 		return nil
 	}
+	if timeout := module.Module.TerragruntOptions.ModuleTimeout; timeout > 0 {
+		module.Module.TerragruntOptions.Deadline = module.startTime.Add(timeout)
+	}
 	module.Module.TerragruntOptions.Logger.Debugf("Running module %s now", module.displayName())
-	return module.Module.TerragruntOptions.RunTerragrunt(module.Module.TerragruntOptions)
+	endSpan := module.Module.TerragruntOptions.StartSpan("module "+util.GetPathRelativeToWorkingDir(module.Module.Path),
+		attribute.String("terragrunt.module.path", module.Module.Path),
+		attribute.Int("terragrunt.worker", module.workerID),
+	)
+	err := module.Module.TerragruntOptions.RunTerragrunt(module.Module.TerragruntOptions)
+	endSpan(err)
+	return err
 }
 
 var separator = strings.Repeat("-", 132)
@@ -270,11 +409,15 @@ var separator = strings.Repeat("-", 132)
 func (module *runningModule) moduleFinished(moduleErr error) {
 	status := "successfully!"
 	logFinish := module.Module.TerragruntOptions.Logger.Infof
+	module.Module.TerragruntOptions.FlushOutput()
 	output := module.OutStream.String()
 
 	if module.Handler != nil {
 		output, moduleErr = module.Handler(*module.Module, output, moduleErr)
 	}
+	module.recordTiming(moduleErr)
+	module.cacheOutputs(moduleErr)
+	module.Events.moduleFinished(module, moduleErr)
 
 	if moduleErr != nil {
 		status = fmt.Sprintf("with an error: %v", moduleErr)
@@ -283,25 +426,82 @@ func (module *runningModule) moduleFinished(moduleErr error) {
 
 	module.Mutex.Lock()
 	defer module.Mutex.Unlock()
+	module.Progress.moduleFinished(module, moduleErr)
 	logFinish("Module %s has finished %s", module.displayName(), status)
 
-	if output == "" {
+	switch {
+	case module.Module.TerragruntOptions.JSONLogs():
+		// The output has already been logged line by line
+	case output == "":
 		module.Module.TerragruntOptions.Logger.Info("No output")
-	} else {
+	default:
 		fmt.Fprintln(module.Writer, color.HiGreenString("%s\n%v\n", separator, module.displayName()))
 		fmt.Fprintln(module.Writer, output)
 	}
 
+	if isModuleFailure(moduleErr) {
+		module.FailureLimit.add()
+	}
 	module.Status = finished
 	module.Err = moduleErr
+	module.endTime = time.Now()
 
 	for _, toNotify := range module.NotifyWhenDone {
 		toNotify.DependencyDone <- module
 	}
 }
 
+// Returns true if the error has been raised by the module itself (i.e. not by one of its dependencies)
+func isModuleFailure(err error) bool {
+	switch tgerrors.Unwrap(err).(type) {
+	case nil, dependencyFinishedWithError, errModuleSkipped, errModuleCancelled, errModuleRejected:
+		return false
+	}
+	return true
+}
+
 // Custom error types
 
+type errModuleSkipped struct {
+	Module *TerraformModule
+}
+
+func (e errModuleSkipped) Error() string {
+	return fmt.Sprintf("Module %s has been skipped because the maximum number of failures has been reached", util.GetPathRelativeToWorkingDirMax(e.Module.Path, 3))
+}
+
+func (e errModuleSkipped) ExitStatus() (int, error) {
+	return errorExitCode, nil
+}
+
+type errModuleCancelled struct {
+	Module *TerraformModule
+}
+
+func (e errModuleCancelled) Error() string {
+	return fmt.Sprintf("Module %s has been cancelled", util.GetPathRelativeToWorkingDirMax(e.Module.Path, 3))
+}
+
+func (e errModuleCancelled) ExitStatus() (int, error) {
+	return errorExitCode, nil
+}
+
+type errModuleRejected struct {
+	Module         *TerraformModule
+	RejectedModule *TerraformModule // The dependency that has been rejected (nil if the module itself has been rejected)
+}
+
+func (e errModuleRejected) Error() string {
+	if e.RejectedModule == nil {
+		return fmt.Sprintf("The plan of module %s has been rejected", util.GetPathRelativeToWorkingDirMax(e.Module.Path, 3))
+	}
+	return fmt.Sprintf("Module %s has been skipped because the plan of its dependency %s has been rejected", util.GetPathRelativeToWorkingDirMax(e.Module.Path, 3), util.GetPathRelativeToWorkingDirMax(e.RejectedModule.Path, 3))
+}
+
+func (e errModuleRejected) ExitStatus() (int, error) {
+	return errorExitCode, nil
+}
+
 type dependencyFinishedWithError struct {
 	Module     *TerraformModule
 	Dependency *TerraformModule
@@ -333,12 +533,20 @@ func (e errMulti) Error() string {
 
 func (e errMulti) ExitStatus() (int, error) {
 	exitCode := normalExitCode
+	timedOut := false
 	for i := range e.Errors {
 		if code, err := shell.GetExitCode(e.Errors[i]); err != nil {
 			return undefinedExitCode, e
 		} else if code > exitCode {
 			exitCode = code
 		}
+		if _, isTimeout := tgerrors.Unwrap(e.Errors[i]).(tgerrors.CommandTimeout); isTimeout {
+			timedOut = true
+		}
+	}
+	if timedOut {
+		// The timeout exit code has precedence to make it possible to distinguish modules that did not complete in time
+		return tgerrors.TimeoutExitCode, nil
 	}
 	return exitCode, nil
 }
@@ -1,8 +1,11 @@
 package configstack
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/coveooss/terragrunt/v2/config"
 	"github.com/coveooss/terragrunt/v2/options"
@@ -358,7 +361,7 @@ func TestToRunningModulesMultipleModulesWithAndWithoutDependenciesReverseOrder(t
 }
 
 func testToRunningModules(t *testing.T, modules []*TerraformModule, order dependencyOrder, expected map[string]*runningModule) {
-	actual, err := toRunningModules(modules, order)
+	actual, err := toRunningModules(modules, order, newScheduler(modules))
 	if assert.Nil(t, err, "For modules %v and order %v", modules, order) {
 		assertRunningModuleMapsEqual(t, expected, actual, true, "For modules %v and order %v", modules, order)
 	}
@@ -1073,3 +1076,228 @@ func TestRunModulesReverseOrderMultipleModulesWithDependenciesLargeGraphPartialF
 	assert.True(t, eRan)
 	assert.True(t, fRan)
 }
+
+func TestRunModulesFailFast(t *testing.T) {
+	t.Parallel()
+
+	aRan := false
+	expectedErrA := fmt.Errorf("Expected error for module a")
+	terragruntOptionsA := optionsWithMockTerragruntCommand("a", expectedErrA, &aRan)
+	terragruntOptionsA.FailFast = true
+	moduleA := &TerraformModule{Path: "a", TerragruntOptions: terragruntOptionsA}
+
+	bRan := false
+	moduleB := &TerraformModule{Path: "b", TerragruntOptions: optionsWithMockTerragruntCommand("b", nil, &bRan)}
+
+	cRan := false
+	moduleC := &TerraformModule{Path: "c", Dependencies: []*TerraformModule{moduleB}, TerragruntOptions: optionsWithMockTerragruntCommand("c", nil, &cRan)}
+
+	modules := []*TerraformModule{moduleA, moduleB, moduleC}
+	runningModules, err := toRunningModules(modules, NormalOrder, newScheduler(modules))
+	assert.NoError(t, err)
+	initWorkers(len(modules))
+
+	// We run the modules one after the other to ensure that a has failed before b and c are scheduled
+	for _, module := range modules {
+		runningModules[module.Path].runModuleWhenReady(context.Background())
+	}
+
+	assertMultiErrorContains(t, collectErrors(runningModules), expectedErrA, errModuleSkipped{moduleB}, errModuleSkipped{moduleC})
+	assert.True(t, aRan)
+	assert.False(t, bRan)
+	assert.False(t, cRan)
+
+	results := collectResults(modules, runningModules)
+	assert.Equal(t, reportFailed, results[0].status())
+	assert.Equal(t, reportSkipped, results[1].status())
+	assert.Equal(t, reportSkipped, results[2].status())
+}
+
+func TestFailureLimit(t *testing.T) {
+	t.Parallel()
+
+	unlimited := &failureLimit{}
+	unlimited.add()
+	assert.False(t, unlimited.reached())
+
+	limit := &failureLimit{max: 2}
+	limit.add()
+	assert.False(t, limit.reached())
+	select {
+	case <-limit.stopped():
+		assert.Fail(t, "The limit is not reached yet")
+	default:
+	}
+	limit.add()
+	assert.True(t, limit.reached())
+	<-limit.stopped()
+	<-(&failureLimit{max: 1, failures: 1}).stopped()
+}
+
+func TestConcurrencyGroups(t *testing.T) {
+	t.Parallel()
+
+	newModule := func(path, group string, limits map[string]int) *TerraformModule {
+		return &TerraformModule{Path: path, Config: config.TerragruntConfig{ConcurrencyGroup: group, ConcurrencyLimits: limits}}
+	}
+	groups := newConcurrencyGroups([]*TerraformModule{
+		newModule("eks", "heavy", map[string]int{"heavy": 3}),
+		newModule("rds", "heavy", map[string]int{"heavy": 2, "light": 20}),
+		newModule("sqs", "light", nil),
+		newModule("sns", "", map[string]int{"": 1}),
+		newModule("iam", "unlimited", map[string]int{"unlimited": 0}),
+	})
+
+	// The smallest limit is used and only the groups that have a limit defined by their modules are restricted
+	assert.Equal(t, concurrencyGroups{"heavy": 2}, groups)
+	assert.False(t, groups.isFull("heavy", 1))
+	assert.True(t, groups.isFull("heavy", 2))
+	assert.False(t, groups.isFull("light", 100))
+	assert.False(t, groups.isFull("", 100))
+}
+
+func TestRunModulesConcurrencyGroup(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	modules := make([]*TerraformModule, 5)
+	for i := range modules {
+		terragruntOptions := options.NewTerragruntOptionsForTest(fmt.Sprintf("module%d", i))
+		terragruntOptions.RunTerragrunt = func(*options.TerragruntOptions) error {
+			mutex.Lock()
+			if running++; running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return nil
+		}
+		modules[i] = &TerraformModule{
+			Path:              fmt.Sprintf("module%d", i),
+			Config:            config.TerragruntConfig{ConcurrencyGroup: "heavy", ConcurrencyLimits: map[string]int{"heavy": 1}},
+			TerragruntOptions: terragruntOptions,
+		}
+	}
+
+	assert.NoError(t, runModules(modules))
+	assert.Equal(t, 1, maxRunning)
+}
+
+// Creates the following modules (b, c and d depend on a, e depends on d):
+//
+//	a <- b
+//	  <- c
+//	  <- d <- e
+//	f
+func TestSchedulerPriority(t *testing.T) {
+	t.Parallel()
+
+	a := &TerraformModule{Path: "a", TerragruntOptions: mockOptions}
+	b := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{a}, TerragruntOptions: mockOptions}
+	c := &TerraformModule{Path: "c", Dependencies: []*TerraformModule{a}, TerragruntOptions: mockOptions, Config: config.TerragruntConfig{ConcurrencyGroup: "heavy", ConcurrencyLimits: map[string]int{"heavy": 1}}}
+	d := &TerraformModule{Path: "d", Dependencies: []*TerraformModule{a}, TerragruntOptions: mockOptions}
+	e := &TerraformModule{Path: "e", Dependencies: []*TerraformModule{d}, TerragruntOptions: mockOptions}
+	f := &TerraformModule{Path: "f", TerragruntOptions: mockOptions}
+
+	runningModules, err := toRunningModules([]*TerraformModule{a, b, c, d, e, f}, NormalOrder, newScheduler([]*TerraformModule{a, b, c, d, e, f}))
+	assert.NoError(t, err)
+	priorities := map[string]float64{}
+	for path, module := range runningModules {
+		priorities[path] = module.priority
+	}
+	assert.Equal(t, map[string]float64{"a": 3, "b": 1, "c": 1, "d": 2, "e": 1, "f": 1}, priorities)
+
+	// The modules are ordered by priority, then by path
+	s := runningModules["a"].Scheduler
+	for _, path := range []string{"f", "e", "c", "b", "d"} {
+		s.push(runningModules[path])
+	}
+	var order []string
+	for _, module := range s.ready {
+		order = append(order, module.Module.Path)
+	}
+	assert.Equal(t, []string{"d", "b", "c", "e", "f"}, order)
+	assert.Equal(t, 0, s.next())
+
+	// Modules in a full concurrency group are not selected
+	s.ready = s.ready[1:]
+	s.running["heavy"] = 1
+	assert.Equal(t, 0, s.next())
+	s.ready = s.ready[1:]
+	assert.Equal(t, 1, s.next())
+}
+
+func TestSchedulerAcquireStopped(t *testing.T) {
+	t.Parallel()
+
+	// The modules of a full concurrency group wait for a worker until they are cancelled or skipped
+	newModule := func(path string) *TerraformModule {
+		return &TerraformModule{Path: path, TerragruntOptions: mockOptions, Config: config.TerragruntConfig{ConcurrencyGroup: "heavy", ConcurrencyLimits: map[string]int{"heavy": 1}}}
+	}
+	modules := []*TerraformModule{newModule("a"), newModule("b")}
+	s := newScheduler(modules)
+	defer s.close()
+	runningModules, err := toRunningModules(modules, NormalOrder, s)
+	assert.NoError(t, err)
+	s.running["heavy"] = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	assert.False(t, s.acquire(ctx, runningModules["a"]))
+
+	runningModules["b"].FailureLimit = &failureLimit{max: 1}
+	time.AfterFunc(50*time.Millisecond, runningModules["b"].FailureLimit.add)
+	assert.False(t, s.acquire(context.Background(), runningModules["b"]))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	assert.Empty(t, s.ready, "The modules should have been removed from the queue")
+}
+
+func TestRunModulesCancelled(t *testing.T) {
+	t.Parallel()
+
+	aRan := false
+	moduleA := &TerraformModule{Path: "a", TerragruntOptions: optionsWithMockTerragruntCommand("a", nil, &aRan)}
+
+	bRan := false
+	moduleB := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{moduleA}, TerragruntOptions: optionsWithMockTerragruntCommand("b", nil, &bRan)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := runModulesWithContext(ctx, []*TerraformModule{moduleA, moduleB}, nil, NormalOrder)
+	assertMultiErrorContains(t, err, errModuleCancelled{moduleA}, errModuleCancelled{moduleB})
+	assert.False(t, aRan)
+	assert.False(t, bRan)
+	assert.Equal(t, reportCancelled, results[0].status())
+	assert.Equal(t, reportCancelled, results[1].status())
+}
+
+func TestRunModulesInterrupted(t *testing.T) {
+	t.Parallel()
+
+	// Module a is interrupted while it is running, its dependents are cancelled instead of failing
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errInterrupted := fmt.Errorf("interrupted")
+	optionsA := options.NewTerragruntOptionsForTest("a")
+	optionsA.RunTerragrunt = func(*options.TerragruntOptions) error {
+		cancel()
+		return errInterrupted
+	}
+	moduleA := &TerraformModule{Path: "a", TerragruntOptions: optionsA}
+
+	bRan := false
+	moduleB := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{moduleA}, TerragruntOptions: optionsWithMockTerragruntCommand("b", nil, &bRan)}
+
+	results, err := runModulesWithContext(ctx, []*TerraformModule{moduleA, moduleB}, nil, NormalOrder)
+	assertMultiErrorContains(t, err, errInterrupted, errModuleCancelled{moduleB})
+	assert.False(t, bRan)
+	assert.Equal(t, reportFailed, results[0].status())
+	assert.Equal(t, reportCancelled, results[1].status())
+}
@@ -0,0 +1,42 @@
+package configstack
+
+import (
+	"encoding/json"
+
+	"github.com/coveooss/terragrunt/v2/shell"
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// The terraform commands that modify the state of a module (its cached outputs are no longer valid once they are run)
+var commandsModifyingState = []string{"apply", "destroy", "import", "refresh", "state", "taint", "untaint"}
+
+// Updates the output cache once the module has been processed. After a successful apply, the outputs are read from the
+// new state (a single terraform state pull, without init), so the modules depending on this one receive fresh values
+// without running terragrunt output on it.
+func (module *runningModule) cacheOutputs(moduleErr error) {
+	terragruntOptions := module.Module.TerragruntOptions
+	command := util.IndexOrDefault(terragruntOptions.TerraformCliArgs, 0, "")
+	if terragruntOptions.OutputCache == nil || module.Module.AssumeAlreadyApplied || !util.ListContainsElement(commandsModifyingState, command) {
+		return
+	}
+
+	terragruntOptions.OutputCache.Invalidate(module.Module.Path)
+	if command != "apply" || moduleErr != nil || len(module.NotifyWhenDone) == 0 {
+		// Nobody is waiting after this module, so the outputs will only be read if they are actually required
+		return
+	}
+
+	// The command is run in the working dir used by the module (it may be a temporary folder if the source is downloaded)
+	state, err := shell.NewTFCmd(terragruntOptions).Args("state", "pull").Output()
+	var serial int64
+	var outputs json.RawMessage
+	if err == nil {
+		serial, outputs, err = util.ParseTerraformState(state)
+	}
+	if err != nil {
+		terragruntOptions.Logger.Debugf("Unable to read the state of %s, its outputs will be read by the modules that need them: %v", util.GetPathRelativeToWorkingDir(module.Module.Path), err)
+		return
+	}
+	terragruntOptions.OutputCache.Set(module.Module.Path, serial, outputs)
+	terragruntOptions.Logger.Debugf("Outputs of %s saved in the cache (serial %d)", util.GetPathRelativeToWorkingDir(module.Module.Path), serial)
+}
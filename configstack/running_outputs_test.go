@@ -0,0 +1,48 @@
+package configstack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunModulesCacheOutputs(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	terraform := filepath.Join(tmpDir, "terraform")
+	script := "#!/bin/sh\n[ \"$1 $2\" = \"state pull\" ] && echo '{\"serial\": 3, \"outputs\": {\"id\": {\"value\": \"vpc-1\", \"type\": \"string\"}}}'\n"
+	assert.NoError(t, os.WriteFile(terraform, []byte(script), 0755))
+
+	cache := options.NewOutputCache()
+	cache.Set(filepath.Join(tmpDir, "a"), 1, []byte(`{"id": {"value": "old"}}`))
+	cache.Set(filepath.Join(tmpDir, "b"), 0, []byte(`{}`))
+	newModule := func(name string, dependencies ...*TerraformModule) *TerraformModule {
+		var executed bool
+		terragruntOptions := optionsWithMockTerragruntCommand(filepath.Join(tmpDir, name, options.DefaultConfigName), nil, &executed)
+		terragruntOptions.TerraformPath = terraform
+		terragruntOptions.TerraformCliArgs = []string{"apply"}
+		terragruntOptions.OutputCache = cache
+		return &TerraformModule{Path: filepath.Join(tmpDir, name), Dependencies: dependencies, TerragruntOptions: terragruntOptions}
+	}
+	moduleA := newModule("a")
+	moduleB := newModule("b", moduleA)
+	assert.NoError(t, os.MkdirAll(moduleA.TerragruntOptions.WorkingDir, 0755))
+
+	_, err := runModulesWithContext(context.Background(), []*TerraformModule{moduleA, moduleB}, nil, NormalOrder)
+	assert.NoError(t, err)
+
+	// The outputs of a are read from its new state since b depends on it
+	entry, found := cache.Get(moduleA.Path)
+	assert.True(t, found)
+	assert.Equal(t, int64(3), entry.Serial)
+	assert.JSONEq(t, `{"id": {"sensitive": false, "type": "string", "value": "vpc-1"}}`, string(entry.Outputs))
+
+	// Nobody depends on b, so its outputs are simply invalidated
+	_, found = cache.Get(moduleB.Path)
+	assert.False(t, found)
+}
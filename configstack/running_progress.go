@@ -0,0 +1,223 @@
+package configstack
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/util"
+	"golang.org/x/term"
+)
+
+const progressRefreshDelay = time.Second
+
+// progressDashboard displays a live status of the modules during the execution of a stack (see --terragrunt-progress).
+// The dashboard is redrawn periodically at the bottom of the terminal and is cleared before anything else is written
+// to the terminal (see wrap). All methods are nil safe, so a nil dashboard can be used when the feature is disabled.
+type progressDashboard struct {
+	mutex    sync.Mutex
+	writer   io.Writer
+	width    int
+	start    time.Time
+	total    int
+	running  map[*runningModule]time.Time
+	finished int
+	failed   int
+	lines    int // The number of lines currently displayed
+	done     chan struct{}
+	stopped  sync.WaitGroup
+}
+
+// Returns a new dashboard if it is enabled and the output is a terminal (returns nil otherwise). The dashboard is never
+// displayed with the JSON log format since the logs are intended to be processed by another tool.
+func newProgressDashboard(modules map[string]*runningModule) *progressDashboard {
+	if len(modules) == 0 {
+		return nil
+	}
+	for _, module := range modules {
+		// The options regarding the dashboard are the same for all modules
+		if !module.Module.TerragruntOptions.Progress || module.Module.TerragruntOptions.JSONLogs() {
+			return nil
+		}
+		break
+	}
+
+	fd := int(os.Stderr.Fd())
+	if !term.IsTerminal(fd) {
+		return nil
+	}
+	width, _, err := term.GetSize(fd)
+	if err != nil || width <= 0 {
+		width = 120
+	}
+
+	return &progressDashboard{
+		writer:  os.Stderr,
+		width:   width,
+		start:   time.Now(),
+		total:   len(modules),
+		running: map[*runningModule]time.Time{},
+		done:    make(chan struct{}),
+	}
+}
+
+// Refresh the dashboard periodically until stop is called
+func (progress *progressDashboard) run() {
+	if progress == nil {
+		return
+	}
+	progress.stopped.Add(1)
+	go func() {
+		defer progress.stopped.Done()
+		ticker := time.NewTicker(progressRefreshDelay)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-progress.done:
+				return
+			case <-ticker.C:
+				progress.mutex.Lock()
+				progress.draw()
+				progress.mutex.Unlock()
+			}
+		}
+	}()
+}
+
+// Stop refreshing the dashboard and remove it from the terminal
+func (progress *progressDashboard) stop() {
+	if progress == nil {
+		return
+	}
+	close(progress.done)
+	progress.stopped.Wait()
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.clear()
+}
+
+// Register that the module is now running
+func (progress *progressDashboard) moduleStarted(module *runningModule) {
+	if progress == nil {
+		return
+	}
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.running[module] = time.Now()
+}
+
+// Register that the module is completed
+func (progress *progressDashboard) moduleFinished(module *runningModule, err error) {
+	if progress == nil {
+		return
+	}
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	delete(progress.running, module)
+	progress.finished++
+	if err != nil {
+		progress.failed++
+	}
+}
+
+// Returns a writer that removes the dashboard from the terminal before writing to the supplied writer (it will be
+// redrawn on the next refresh). Returns the supplied writer as is if the dashboard is disabled.
+func (progress *progressDashboard) wrap(writer io.Writer) io.Writer {
+	if progress == nil {
+		return writer
+	}
+	return progressWriter{progress, writer}
+}
+
+type progressWriter struct {
+	progress *progressDashboard
+	writer   io.Writer
+}
+
+func (writer progressWriter) Write(p []byte) (int, error) {
+	writer.progress.mutex.Lock()
+	defer writer.progress.mutex.Unlock()
+	writer.progress.clear()
+	return writer.writer.Write(p)
+}
+
+// Remove the dashboard from the terminal (the caller must hold the lock)
+func (progress *progressDashboard) clear() {
+	if progress.lines == 0 {
+		return
+	}
+	fmt.Fprintf(progress.writer, "\033[%dA\033[J", progress.lines)
+	progress.lines = 0
+}
+
+// Replace the dashboard currently displayed by the current status (the caller must hold the lock)
+func (progress *progressDashboard) draw() {
+	lines := progress.render(time.Now())
+	progress.clear()
+	for _, line := range lines {
+		fmt.Fprintln(progress.writer, line)
+	}
+	progress.lines = len(lines)
+}
+
+var ansiEscapeSequence = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+
+// Returns the lines of the dashboard
+func (progress *progressDashboard) render(now time.Time) []string {
+	waiting := progress.total - progress.finished - len(progress.running)
+	lines := []string{fmt.Sprintf("%s elapsed - %d waiting, %d running, %d finished, %d failed",
+		now.Sub(progress.start).Round(time.Second), waiting, len(progress.running), progress.finished-progress.failed, progress.failed)}
+
+	modules := make([]*runningModule, 0, len(progress.running))
+	for module := range progress.running {
+		modules = append(modules, module)
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].workerID < modules[j].workerID })
+
+	for _, module := range modules {
+		line := fmt.Sprintf("  Worker #%d: %s (%s)", module.workerID, util.GetPathRelativeToWorkingDirMax(module.Module.Path, 3), now.Sub(progress.running[module]).Round(time.Second))
+		if output := ansiEscapeSequence.ReplaceAllString(module.lastOutput.String(), ""); output != "" {
+			line += " " + output
+		}
+		lines = append(lines, truncate(line, progress.width))
+	}
+	return lines
+}
+
+// Ensure that the line fits in the terminal width (otherwise, the number of lines to clear would be wrong)
+func truncate(line string, width int) string {
+	if runes := []rune(line); len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return line
+}
+
+// lastLineWriter keeps the last non empty line written to it
+type lastLineWriter struct {
+	mutex sync.Mutex
+	line  string
+}
+
+func (writer *lastLineWriter) Write(p []byte) (int, error) {
+	lines := strings.Split(strings.ReplaceAll(string(p), "\r", "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			writer.mutex.Lock()
+			writer.line = line
+			writer.mutex.Unlock()
+			break
+		}
+	}
+	return len(p), nil
+}
+
+func (writer *lastLineWriter) String() string {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	return writer.line
+}
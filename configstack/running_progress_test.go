@@ -0,0 +1,56 @@
+package configstack
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressDashboard(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	start := time.Now()
+	progress := &progressDashboard{
+		writer:  &output,
+		width:   60,
+		start:   start,
+		total:   5,
+		running: map[*runningModule]time.Time{},
+	}
+
+	network := &runningModule{Module: &TerraformModule{Path: "network"}, workerID: 2, lastOutput: &lastLineWriter{}}
+	backend := &runningModule{Module: &TerraformModule{Path: "backend"}, workerID: 1, lastOutput: &lastLineWriter{}}
+	fmt.Fprint(backend.lastOutput, "\x1b[32mPlan: 1 to add, 0 to change, 0 to destroy.\x1b[0m\n\n")
+	fmt.Fprint(network.lastOutput, "Refreshing state... [id=vpc-12345678901234567890123456789]\n")
+	progress.running[network] = start.Add(10 * time.Second)
+	progress.running[backend] = start.Add(30 * time.Second)
+	progress.moduleFinished(&runningModule{}, nil)
+	progress.moduleFinished(&runningModule{}, fmt.Errorf("failure"))
+
+	assert.Equal(t, []string{
+		"1m0s elapsed - 1 waiting, 2 running, 1 finished, 1 failed",
+		"  Worker #1: backend (30s) Plan: 1 to add, 0 to change, 0 t…",
+		"  Worker #2: network (50s) Refreshing state... [id=vpc-1234…",
+	}, progress.render(start.Add(time.Minute)))
+
+	progress.draw()
+	assert.Equal(t, 3, progress.lines)
+
+	// The dashboard is cleared before writing anything else
+	output.Reset()
+	fmt.Fprintln(progress.wrap(&output), "Module backend has finished successfully!")
+	assert.Equal(t, "\x1b[3A\x1b[JModule backend has finished successfully!\n", output.String())
+	assert.Equal(t, 0, progress.lines)
+
+	// A nil dashboard does nothing
+	var disabled *progressDashboard
+	disabled.run()
+	disabled.moduleStarted(network)
+	disabled.moduleFinished(network, nil)
+	disabled.stop()
+	assert.Equal(t, &output, disabled.wrap(&output))
+}
@@ -0,0 +1,164 @@
+package configstack
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// scheduler dispatches the workers to the modules that are ready to run (i.e. whose dependencies are completed).
+// When several modules are ready, the worker is given to the one with the highest priority that is not restricted
+// by its concurrency group.
+//
+// The priority of a module is the estimated duration of the longest chain of modules that are waiting after it
+// (including itself). Starting these modules first reduces the total duration of the stack execution.
+type scheduler struct {
+	mutex     sync.Mutex
+	cond      *sync.Cond
+	ready     []*runningModule // The modules waiting for a worker, sorted by priority
+	groups    concurrencyGroups
+	running   map[string]int     // The number of modules currently running in each concurrency group
+	estimates map[string]float64 // The estimated duration of each module (based on the timing history)
+	started   sync.Once
+	closed    bool
+}
+
+func newScheduler(modules []*TerraformModule) *scheduler {
+	s := &scheduler{groups: newConcurrencyGroups(modules), running: map[string]int{}, estimates: estimateDurations(modules)}
+	s.cond = sync.NewCond(&s.mutex)
+	return s
+}
+
+// Compute the priority of each module. This must be called once the modules are cross linked.
+func (s *scheduler) prioritize(modules map[string]*runningModule) {
+	visited := make(map[*runningModule]bool, len(modules))
+	var compute func(*runningModule) float64
+	compute = func(module *runningModule) float64 {
+		if visited[module] {
+			return module.priority
+		}
+		visited[module] = true
+		var longest float64
+		for _, dependent := range module.NotifyWhenDone {
+			if priority := compute(dependent); priority > longest {
+				longest = priority
+			}
+		}
+		module.priority = longest + s.estimates[module.Module.Path]
+		return module.priority
+	}
+
+	for _, module := range modules {
+		compute(module)
+	}
+}
+
+// Add the module to the ready queue and wait until a worker is assigned to it (module.workerID). The worker must be
+// released by calling release once the module is completed. Returns false if the context is cancelled or if the maximum
+// number of failures is reached before a worker is assigned, the module is then removed from the queue and it must not
+// be released.
+func (s *scheduler) acquire(ctx context.Context, module *runningModule) bool {
+	s.started.Do(func() { go s.dispatch() })
+
+	module.worker = make(chan int, 1)
+	s.mutex.Lock()
+	s.push(module)
+	s.cond.Broadcast()
+	s.mutex.Unlock()
+
+	select {
+	case module.workerID = <-module.worker:
+		return true
+	case <-ctx.Done():
+	case <-module.FailureLimit.stopped():
+	}
+
+	s.mutex.Lock()
+	for i := range s.ready {
+		if s.ready[i] == module {
+			s.ready = append(s.ready[:i], s.ready[i+1:]...)
+			s.mutex.Unlock()
+			return false
+		}
+	}
+	s.mutex.Unlock()
+	// The worker has been assigned in the meantime
+	module.workerID = <-module.worker
+	return true
+}
+
+// Insert the module in the ready queue according to its priority. The caller must hold the lock.
+func (s *scheduler) push(module *runningModule) {
+	index := sort.Search(len(s.ready), func(i int) bool { return s.ready[i].hasLowerPriorityThan(module) })
+	s.ready = append(s.ready, nil)
+	copy(s.ready[index+1:], s.ready[index:])
+	s.ready[index] = module
+}
+
+// Give back the worker assigned to the module
+func (s *scheduler) release(module *runningModule) {
+	freeWorker(module.workerID)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.running[module.Module.Config.ConcurrencyGroup]--
+	s.cond.Broadcast()
+}
+
+// Stop the dispatcher, must be called once all modules are completed
+func (s *scheduler) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+}
+
+// Assign the workers to the ready modules until the scheduler is closed. A worker is only reserved when there is a
+// module that can be started, so the workers are never held while waiting for concurrency groups.
+func (s *scheduler) dispatch() {
+	for {
+		s.mutex.Lock()
+		for !s.closed && s.next() < 0 {
+			s.cond.Wait()
+		}
+		closed := s.closed
+		s.mutex.Unlock()
+		if closed {
+			return
+		}
+
+		worker := waitWorker()
+
+		// Modules with a higher priority may have been added while we were waiting for the worker
+		s.mutex.Lock()
+		index := s.next()
+		if index < 0 {
+			s.mutex.Unlock()
+			freeWorker(worker)
+			continue
+		}
+		module := s.ready[index]
+		s.ready = append(s.ready[:index], s.ready[index+1:]...)
+		s.running[module.Module.Config.ConcurrencyGroup]++
+		s.mutex.Unlock()
+		module.worker <- worker
+	}
+}
+
+// Returns the index of the module with the highest priority that can be started (-1 if there is none).
+// The caller must hold the lock.
+func (s *scheduler) next() int {
+	for i, module := range s.ready {
+		if group := module.Module.Config.ConcurrencyGroup; !s.groups.isFull(group, s.running[group]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Modules with the same priority are ordered by path to get a predictable order
+func (module *runningModule) hasLowerPriorityThan(other *runningModule) bool {
+	if module.priority != other.priority {
+		return module.priority < other.priority
+	}
+	return module.Module.Path > other.Module.Path
+}
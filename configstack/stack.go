@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/coveooss/terragrunt/v2/options"
 )
@@ -14,6 +15,9 @@ import (
 type Stack struct {
 	Path    string
 	Modules []*TerraformModule
+
+	runState *runState          // Used to record the completed modules (see EnableRunState)
+	excluded []*TerraformModule // The modules removed from the stack by the filters
 }
 
 // Render this stack as a human-readable string
@@ -71,7 +75,12 @@ func (stack *Stack) topologicalSort(module *TerraformModule, visitedModules map[
 // Plan all the modules in the given stack in their specified order.
 func (stack *Stack) Plan(command string, terragruntOptions *options.TerragruntOptions) error {
 	stack.setTerraformCommand([]string{command})
-	return stack.planWithSummary(terragruntOptions)
+	cleanup, err := stack.setPlanFile()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	return stack.planWithSummary(command, terragruntOptions)
 }
 
 // Output prints the outputs of all the modules in the given stack in their specified order.
@@ -83,13 +92,18 @@ func (stack *Stack) Output(command string, terragruntOptions *options.Terragrunt
 		}
 		return output, err
 	}
-	return runModulesWithHandler(stack.Modules, handler, NormalOrder)
+	startTime := time.Now()
+	results, err := runModulesWithResults(stack.Modules, handler, NormalOrder)
+	return stack.writeReport(terragruntOptions, command, startTime, results, err)
 }
 
 // RunAll runs the specified command on all modules in the given stack in their specified order.
 func (stack *Stack) RunAll(command []string, terragruntOptions *options.TerragruntOptions, order dependencyOrder) error {
 	stack.setTerraformCommand(command)
-	return runModulesWithHandler(stack.Modules, nil, order)
+	startTime := time.Now()
+	results, err := runModulesWithResults(stack.Modules, stack.runState.handler(), order)
+	stack.runState.finish(terragruntOptions, err)
+	return stack.writeReport(terragruntOptions, strings.Join(command, " "), startTime, results, err)
 }
 
 // Return an error if there is a dependency cycle in the modules of this stack.
@@ -118,6 +132,10 @@ func FindStackInSubfolders(terragruntOptions *options.TerragruntOptions) (*Stack
 		return nil, err
 	}
 
+	if err := stack.filterModules(terragruntOptions); err != nil {
+		return nil, err
+	}
+
 	return stack, nil
 }
 
@@ -0,0 +1,124 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/shell"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// ApplyEach applies the modules of the stack once the user has approved the plan of each module. A module is planned
+// as soon as its dependencies have been applied, then its plan summary is displayed and the user is asked to confirm
+// it before the saved plan is applied. If the plan is rejected, the module and its dependents are skipped.
+func (stack *Stack) ApplyEach(command []string, terragruntOptions *options.TerragruntOptions) error {
+	return stack.applyEach(command, terragruntOptions, &planApproval{prompt: shell.PromptUserForYesNo})
+}
+
+func (stack *Stack) applyEach(command []string, terragruntOptions *options.TerragruntOptions, approval *planApproval) error {
+	stack.setTerraformCommand(command)
+	for _, module := range stack.Modules {
+		module.TerragruntOptions.RunTerragrunt = approval.wrap(module, module.TerragruntOptions.RunTerragrunt)
+	}
+
+	// The pending prompts are abandoned when the execution is interrupted
+	ctx, cancel := newInterruptContext(terragruntOptions.Logger)
+	defer cancel()
+	approval.ctx = ctx
+	approval.turn = make(chan struct{}, 1)
+
+	startTime := time.Now()
+	results, err := runModulesWithContext(ctx, stack.Modules, stack.runState.handler(), NormalOrder)
+	stack.runState.finish(terragruntOptions, err)
+	return stack.writeReport(terragruntOptions, strings.Join(command, " "), startTime, results, err)
+}
+
+// planApproval asks the user to approve the plan of each module (the prompts are serialized across the workers)
+type planApproval struct {
+	prompt func(string, *options.TerragruntOptions) (bool, error)
+	ctx    context.Context // The modules waiting for approval are cancelled when this context is cancelled
+	turn   chan struct{}   // Held by the module that is prompting the user
+}
+
+// Returns a function that plans the module, asks the user to approve the plan and applies it
+func (approval *planApproval) wrap(module *TerraformModule, runTerragrunt func(*options.TerragruntOptions) error) func(*options.TerragruntOptions) error {
+	return func(terragruntOptions *options.TerragruntOptions) error {
+		// The plan is saved outside of the working folder since it may be refreshed when the module is applied
+		file, err := os.CreateTemp("", "terragrunt-*.tfplan")
+		if err != nil {
+			return tgerrors.WithStackTrace(err)
+		}
+		file.Close()
+		planFile := file.Name()
+		defer os.Remove(planFile)
+
+		planOptions := *terragruntOptions
+		planOptions.TerraformCliArgs = append([]string{"plan", "-out=" + planFile}, terragruntOptions.TerraformCliArgs[1:]...)
+		if err := runTerragrunt(&planOptions); err != nil {
+			return err
+		}
+
+		summary := getPlanSummary(TerraformModule{Path: module.Path, TerragruntOptions: &planOptions})
+		if summary.AreChangesKnown && summary.NumberOfChanges == 0 {
+			terragruntOptions.Logger.Infof("No change to approve for %s", util.GetPathRelativeToWorkingDir(module.Path))
+		} else {
+			waitStart := time.Now()
+			approved, err := approval.approve(module, summary, terragruntOptions)
+			if err != nil {
+				return err
+			} else if !approved {
+				return errModuleRejected{Module: module}
+			}
+			if !terragruntOptions.Deadline.IsZero() {
+				// The time spent waiting for the user does not count in the module timeout
+				terragruntOptions.Deadline = terragruntOptions.Deadline.Add(time.Since(waitStart))
+			}
+		}
+
+		// The saved plan must be the last argument of terraform apply
+		terragruntOptions.TerraformCliArgs = append(terragruntOptions.TerraformCliArgs, planFile)
+		return runTerragrunt(terragruntOptions)
+	}
+}
+
+// Displays the plan summary of the module and asks the user to approve it. The module is cancelled if the execution
+// is interrupted while it is waiting for its turn or for the answer of the user.
+func (approval *planApproval) approve(module *TerraformModule, summary planSummary, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	lines := []string{separator, fmt.Sprintf("%s: %s", util.GetPathRelativeToWorkingDir(module.Path), summary.Message)}
+	for _, change := range summary.ResourceChanges {
+		lines = append(lines, change.format())
+	}
+	lines = append(lines, fmt.Sprintf("Do you want to apply the plan of %s?", util.GetPathRelativeToWorkingDir(module.Path)))
+
+	select {
+	case approval.turn <- struct{}{}:
+	case <-approval.ctx.Done():
+		return false, errModuleCancelled{module}
+	}
+
+	type answer struct {
+		approved bool
+		err      error
+	}
+	answers := make(chan answer, 1)
+	go func() {
+		// The turn is only released once the prompt has returned, so an abandoned prompt does not compete with the
+		// next one for the standard input
+		defer func() { <-approval.turn }()
+		approved, err := approval.prompt(strings.Join(lines, "\n"), terragruntOptions)
+		answers <- answer{approved, err}
+	}()
+
+	select {
+	case answer := <-answers:
+		return answer.approved, answer.err
+	case <-approval.ctx.Done():
+		terragruntOptions.Logger.Warningf("The approval of %s has been cancelled", util.GetPathRelativeToWorkingDir(module.Path))
+		return false, errModuleCancelled{module}
+	}
+}
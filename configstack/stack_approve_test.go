@@ -0,0 +1,114 @@
+package configstack
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyEach(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	commands := map[string][]string{}
+	newModule := func(name string, dependencies ...*TerraformModule) *TerraformModule {
+		terragruntOptions := options.NewTerragruntOptionsForTest("/stack/" + name + "/" + options.DefaultConfigName)
+		terragruntOptions.RunTerragrunt = func(terragruntOptions *options.TerragruntOptions) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			commands[name] = append(commands[name], terragruntOptions.TerraformCliArgs[0])
+			if terragruntOptions.TerraformCliArgs[0] == "apply" {
+				// The saved plan is applied
+				assert.True(t, strings.HasSuffix(terragruntOptions.TerraformCliArgs[len(terragruntOptions.TerraformCliArgs)-1], ".tfplan"))
+			}
+			return nil
+		}
+		return &TerraformModule{Path: "/stack/" + name, Dependencies: dependencies, TerragruntOptions: terragruntOptions}
+	}
+	vpc := newModule("vpc")
+	app := newModule("app", vpc)
+	dns := newModule("dns")
+	stack := &Stack{Path: "/stack", Modules: []*TerraformModule{vpc, app, dns}}
+
+	var prompts []string
+	approval := &planApproval{prompt: func(prompt string, _ *options.TerragruntOptions) (bool, error) {
+		prompts = append(prompts, prompt)
+		return !strings.HasSuffix(prompt, "/vpc?"), nil
+	}}
+	err := stack.applyEach([]string{"apply", "-input=false"}, options.NewTerragruntOptionsForTest("/stack"), approval)
+
+	assertMultiErrorContains(t, err, errModuleRejected{Module: vpc}, errModuleRejected{app, vpc})
+	assert.Len(t, prompts, 2)
+	assert.Equal(t, map[string][]string{
+		"vpc": {"plan"},
+		"dns": {"plan", "apply"},
+	}, commands)
+}
+
+func TestPlanApprovalDeadline(t *testing.T) {
+	t.Parallel()
+
+	deadline := time.Now().Add(time.Hour)
+	var applyDeadline time.Time
+	terragruntOptions := options.NewTerragruntOptionsForTest("/stack/vpc/" + options.DefaultConfigName)
+	terragruntOptions.TerraformCliArgs = []string{"apply"}
+	terragruntOptions.Deadline = deadline
+	module := &TerraformModule{Path: "/stack/vpc", TerragruntOptions: terragruntOptions}
+
+	approval := &planApproval{
+		prompt: func(string, *options.TerragruntOptions) (bool, error) {
+			time.Sleep(100 * time.Millisecond)
+			return true, nil
+		},
+		ctx:  context.Background(),
+		turn: make(chan struct{}, 1),
+	}
+	run := approval.wrap(module, func(terragruntOptions *options.TerragruntOptions) error {
+		applyDeadline = terragruntOptions.Deadline
+		return nil
+	})
+	assert.NoError(t, run(terragruntOptions))
+
+	// The time spent waiting for the approval is added to the deadline of the module
+	assert.True(t, applyDeadline.Sub(deadline) >= 100*time.Millisecond, "The deadline should have been postponed")
+}
+
+func TestPlanApprovalCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	approval := &planApproval{
+		prompt: func(string, *options.TerragruntOptions) (bool, error) {
+			// The user never answers
+			<-release
+			return true, nil
+		},
+		ctx:  ctx,
+		turn: make(chan struct{}, 1),
+	}
+
+	newModule := func(name string) *TerraformModule {
+		return &TerraformModule{Path: "/stack/" + name, TerragruntOptions: options.NewTerragruntOptionsForTest("/stack/" + name + "/" + options.DefaultConfigName)}
+	}
+	errs := make(chan error, 2)
+	for _, module := range []*TerraformModule{newModule("vpc"), newModule("app")} {
+		go func(module *TerraformModule) {
+			_, err := approval.approve(module, planSummary{}, module.TerragruntOptions)
+			errs <- err
+		}(module)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	for i := 0; i < 2; i++ {
+		// Both the module that is prompting and the one waiting for its turn are cancelled
+		assert.IsType(t, errModuleCancelled{}, <-errs)
+	}
+}
@@ -0,0 +1,155 @@
+package configstack
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/shell"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// Returns the modules affected by the files changed since the specified git reference (including their dependents)
+func (stack *Stack) getChangedModules(terragruntOptions *options.TerragruntOptions) (map[string]bool, error) {
+	changedFiles, err := getChangedFiles(terragruntOptions, terragruntOptions.ChangedSince)
+	if err != nil {
+		return nil, err
+	}
+	terragruntOptions.Logger.Debugf("%d file(s) changed since %s", len(changedFiles), terragruntOptions.ChangedSince)
+	return stack.getModulesAffectedBy(changedFiles), nil
+}
+
+// Returns the modules affected by the changed files (including their dependents)
+func (stack *Stack) getModulesAffectedBy(changedFiles []string) map[string]bool {
+	// The paths are compared once their symbolic links are resolved since git reports the real path of the files
+	resolvedFiles := make([]string, len(changedFiles))
+	for i, file := range changedFiles {
+		resolvedFiles[i] = resolveSymlinks(file)
+	}
+	modulePaths := make(map[*TerraformModule]string, len(stack.Modules))
+	for _, module := range stack.Modules {
+		modulePaths[module] = resolveSymlinks(module.Path)
+	}
+
+	changed := make(map[string]bool, len(stack.Modules))
+	for _, module := range stack.Modules {
+		if stack.isModuleChanged(module, modulePaths, resolvedFiles) {
+			changed[module.Path] = true
+		}
+	}
+
+	// We add the modules that depend on the changed modules
+	for _, module := range stack.Modules {
+		if changed[module.Path] {
+			addTransitiveDependents(module, stack.Modules, changed)
+		}
+	}
+	return changed
+}
+
+// Check if any of the changed files are in the module folder (but not in a sub module) or in one of the paths
+// watched by the module configuration
+func (stack *Stack) isModuleChanged(module *TerraformModule, modulePaths map[*TerraformModule]string, changedFiles []string) bool {
+	watchedPaths := module.Config.WatchedPaths(module.Path)
+	for i := range watchedPaths {
+		watchedPaths[i] = resolveSymlinks(watchedPaths[i])
+	}
+	for _, file := range changedFiles {
+		if owner := stack.getOwnerModule(file, modulePaths); owner == module {
+			return true
+		}
+		for _, path := range watchedPaths {
+			if file == path || strings.HasPrefix(file, path+string(filepath.Separator)) {
+				return true
+			}
+			if match, _ := filepath.Match(path, file); match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the deepest module that contains the file
+func (stack *Stack) getOwnerModule(file string, modulePaths map[*TerraformModule]string) (owner *TerraformModule) {
+	for _, module := range stack.Modules {
+		path := modulePaths[module]
+		if strings.HasPrefix(file, path+string(filepath.Separator)) && (owner == nil || len(path) > len(modulePaths[owner])) {
+			owner = module
+		}
+	}
+	return
+}
+
+// Returns the path with its symbolic links resolved. If the path does not exist (i.e. a deleted file or a glob pattern),
+// the symbolic links of its parent folders are resolved.
+func resolveSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	if parent := filepath.Dir(path); parent != path {
+		return filepath.Join(resolveSymlinks(parent), filepath.Base(path))
+	}
+	return path
+}
+
+// Add all modules that depend directly or indirectly on the module to the set of paths
+func addTransitiveDependents(module *TerraformModule, modules []*TerraformModule, paths map[string]bool) {
+	for _, other := range modules {
+		if paths[other.Path] {
+			continue
+		}
+		for _, dependency := range other.Dependencies {
+			if dependency.Path == module.Path {
+				paths[other.Path] = true
+				addTransitiveDependents(other, modules, paths)
+				break
+			}
+		}
+	}
+}
+
+// Returns the absolute path of the files changed (or added) since the git reference
+func getChangedFiles(terragruntOptions *options.TerragruntOptions, ref string) ([]string, error) {
+	git := func(args ...string) ([]string, error) {
+		cmd := shell.NewCmd(terragruntOptions, "git").Args(args...)
+		out, err := cmd.Output()
+		if err != nil {
+			terragruntOptions.Logger.Error(out)
+			return nil, err
+		}
+		return util.RemoveElementFromList(strings.Split(strings.TrimSpace(out), "\n"), ""), nil
+	}
+
+	root, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	if len(root) == 0 {
+		return nil, tgerrors.WithStackTrace(errNotInGitRepository(terragruntOptions.WorkingDir))
+	}
+
+	changed, err := git("-C", root[0], "diff", "--name-only", ref)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git("-C", root[0], "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(changed)+len(untracked))
+	for _, file := range append(changed, untracked...) {
+		files = append(files, filepath.Join(root[0], filepath.FromSlash(file)))
+	}
+	return files, nil
+}
+
+// Custom error types
+
+type errNotInGitRepository string
+
+func (err errNotInGitRepository) Error() string {
+	return "Folder " + string(err) + " is not in a git repository"
+}
@@ -0,0 +1,78 @@
+package configstack
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetModulesAffectedBy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		files    []string
+		expected []string
+	}{
+		{
+			name: "no change",
+		},
+		{
+			name:     "file in module",
+			files:    []string{"/stack/app/backend/main.tf"},
+			expected: []string{"/stack/app/backend", "/stack/app/frontend"},
+		},
+		{
+			name:     "file in parent folder of modules",
+			files:    []string{"/stack/app/README.md"},
+			expected: nil,
+		},
+		{
+			name:     "watched file",
+			files:    []string{"/stack/common/variables.tf"},
+			expected: []string{"/stack/dns"},
+		},
+		{
+			name:     "dependency changed",
+			files:    []string{"/stack/network/vpc/main.tf", "/stack/dns/main.tf"},
+			expected: []string{"/stack/app/backend", "/stack/app/frontend", "/stack/dns", "/stack/network"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			stack := createFilterTestStack()
+			stack.Modules[3].Config = config.TerragruntConfig{ConfigFiles: []string{"/stack/common/*.tf"}}
+
+			var actual []string
+			for path := range stack.getModulesAffectedBy(testCase.files) {
+				actual = append(actual, path)
+			}
+			sort.Strings(actual)
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestGetModulesAffectedBySymlinkedStack(t *testing.T) {
+	t.Parallel()
+
+	// git reports the real path of the files while the stack is accessed through a symbolic link
+	folder, err := filepath.EvalSymlinks(t.TempDir())
+	assert.NoError(t, err)
+	realStack, linkedStack := filepath.Join(folder, "real"), filepath.Join(folder, "linked")
+	assert.NoError(t, os.MkdirAll(filepath.Join(realStack, "network"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(realStack, "dns"), 0755))
+	assert.NoError(t, os.Symlink(realStack, linkedStack))
+
+	network := &TerraformModule{Path: filepath.Join(linkedStack, "network")}
+	dns := &TerraformModule{Path: filepath.Join(linkedStack, "dns"), Dependencies: []*TerraformModule{network}}
+	stack := &Stack{Path: linkedStack, Modules: []*TerraformModule{network, dns}}
+
+	changed := stack.getModulesAffectedBy([]string{filepath.Join(realStack, "network", "deleted.tf")})
+	assert.Equal(t, map[string]bool{network.Path: true, dns.Path: true}, changed)
+}
@@ -0,0 +1,135 @@
+package configstack
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// Reduce the stack to the modules selected by the include/exclude filters specified in the options
+func (stack *Stack) filterModules(terragruntOptions *options.TerragruntOptions) error {
+	if len(terragruntOptions.IncludeDirs)+len(terragruntOptions.ExcludeDirs) == 0 && terragruntOptions.OnlyDependenciesOf == "" && terragruntOptions.ChangedSince == "" {
+		return nil
+	}
+
+	var dependenciesOf map[string]bool
+	if terragruntOptions.OnlyDependenciesOf != "" {
+		target, err := util.CanonicalPath(terragruntOptions.OnlyDependenciesOf, terragruntOptions.WorkingDir)
+		if err != nil {
+			return err
+		}
+		var targetModule *TerraformModule
+		for _, module := range stack.Modules {
+			if module.Path == target {
+				targetModule = module
+				break
+			}
+		}
+		if targetModule == nil {
+			return tgerrors.WithStackTrace(errModuleNotFound(terragruntOptions.OnlyDependenciesOf))
+		}
+		dependenciesOf = map[string]bool{}
+		addTransitiveDependencies(targetModule, dependenciesOf)
+	}
+
+	var changed map[string]bool
+	if terragruntOptions.ChangedSince != "" {
+		var err error
+		if changed, err = stack.getChangedModules(terragruntOptions); err != nil {
+			return err
+		}
+	}
+
+	selected := make(map[string]bool, len(stack.Modules))
+	for _, module := range stack.Modules {
+		path, err := util.GetPathRelativeTo(module.Path, terragruntOptions.WorkingDir)
+		if err != nil {
+			return err
+		}
+		if len(terragruntOptions.IncludeDirs) > 0 && !matchPathPatterns(terragruntOptions.IncludeDirs, path, terragruntOptions.WorkingDir) {
+			continue
+		}
+		if matchPathPatterns(terragruntOptions.ExcludeDirs, path, terragruntOptions.WorkingDir) {
+			continue
+		}
+		if dependenciesOf != nil && !dependenciesOf[module.Path] {
+			continue
+		}
+		if changed != nil && !changed[module.Path] {
+			continue
+		}
+		selected[module.Path] = true
+	}
+
+	stack.keepModules(selected, terragruntOptions)
+	return nil
+}
+
+// Reduce the stack to the selected modules. The dependencies of the selected modules that are not selected
+// themselves are kept in the stack, but they are considered as already applied.
+func (stack *Stack) keepModules(selected map[string]bool, terragruntOptions *options.TerragruntOptions) {
+	required := make(map[string]bool, len(stack.Modules))
+	for _, module := range stack.Modules {
+		if selected[module.Path] {
+			addTransitiveDependencies(module, required)
+		}
+	}
+
+	modules := make([]*TerraformModule, 0, len(required))
+	for _, module := range stack.Modules {
+		if !required[module.Path] {
+			terragruntOptions.Logger.Debugf("Module %s excluded from the stack", util.GetPathRelativeToWorkingDirMax(module.Path, 3))
+			stack.excluded = append(stack.excluded, module)
+			continue
+		}
+		if !selected[module.Path] && !module.AssumeAlreadyApplied {
+			terragruntOptions.Logger.Debugf("Module %s is not selected but is required by other modules, assuming it has already been applied", util.GetPathRelativeToWorkingDirMax(module.Path, 3))
+			module.AssumeAlreadyApplied = true
+		}
+		modules = append(modules, module)
+	}
+	stack.Modules = modules
+}
+
+// Add the module and all its transitive dependencies to the set of paths
+func addTransitiveDependencies(module *TerraformModule, paths map[string]bool) {
+	if paths[module.Path] {
+		return
+	}
+	paths[module.Path] = true
+	for _, dependency := range module.Dependencies {
+		addTransitiveDependencies(dependency, paths)
+	}
+}
+
+// Check if the path (or any of its parent folders) matches one of the glob patterns
+func matchPathPatterns(patterns []string, path string, workingDir string) bool {
+	for _, pattern := range patterns {
+		pattern = filepath.ToSlash(pattern)
+		if filepath.IsAbs(pattern) {
+			if relative, err := util.GetPathRelativeTo(pattern, workingDir); err == nil {
+				pattern = relative
+			}
+		}
+		pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+
+		for current := path; current != "." && current != "/" && current != ""; current = filepath.ToSlash(filepath.Dir(current)) {
+			if match, _ := filepath.Match(pattern, current); match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Custom error types
+
+type errModuleNotFound string
+
+func (err errModuleNotFound) Error() string {
+	return fmt.Sprintf("Could not find module %s in the stack", string(err))
+}
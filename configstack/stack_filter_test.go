@@ -0,0 +1,104 @@
+package configstack
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Creates the following stack:
+//
+//	/stack/network <- /stack/app/backend <- /stack/app/frontend
+//	/stack/dns
+func createFilterTestStack() *Stack {
+	network := &TerraformModule{Path: "/stack/network"}
+	backend := &TerraformModule{Path: "/stack/app/backend", Dependencies: []*TerraformModule{network}}
+	frontend := &TerraformModule{Path: "/stack/app/frontend", Dependencies: []*TerraformModule{backend}}
+	dns := &TerraformModule{Path: "/stack/dns"}
+	return &Stack{Path: "/stack", Modules: []*TerraformModule{network, backend, frontend, dns}}
+}
+
+func TestFilterModules(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		include            []string
+		exclude            []string
+		onlyDependenciesOf string
+		expected           []string
+		expectedAssumed    []string
+		expectedErr        error
+	}{
+		{
+			name:     "no filter",
+			expected: []string{"/stack/app/backend", "/stack/app/frontend", "/stack/dns", "/stack/network"},
+		},
+		{
+			name:            "include parent folder",
+			include:         []string{"app"},
+			expected:        []string{"/stack/app/backend", "/stack/app/frontend", "/stack/network"},
+			expectedAssumed: []string{"/stack/network"},
+		},
+		{
+			name:            "include with glob",
+			include:         []string{"app/front*", "d?s"},
+			expected:        []string{"/stack/app/backend", "/stack/app/frontend", "/stack/dns", "/stack/network"},
+			expectedAssumed: []string{"/stack/app/backend", "/stack/network"},
+		},
+		{
+			name:     "exclude",
+			exclude:  []string{"./app/", "/stack/dns"},
+			expected: []string{"/stack/network"},
+		},
+		{
+			name:            "exclude a dependency",
+			exclude:         []string{"network"},
+			expected:        []string{"/stack/app/backend", "/stack/app/frontend", "/stack/dns", "/stack/network"},
+			expectedAssumed: []string{"/stack/network"},
+		},
+		{
+			name:               "only dependencies of",
+			onlyDependenciesOf: "app/backend",
+			expected:           []string{"/stack/app/backend", "/stack/network"},
+		},
+		{
+			name:               "only dependencies of unknown module",
+			onlyDependenciesOf: "unknown",
+			expectedErr:        errModuleNotFound("unknown"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			stack := createFilterTestStack()
+			terragruntOptions := options.NewTerragruntOptionsForTest("/stack")
+			terragruntOptions.WorkingDir = "/stack"
+			terragruntOptions.IncludeDirs = testCase.include
+			terragruntOptions.ExcludeDirs = testCase.exclude
+			terragruntOptions.OnlyDependenciesOf = testCase.onlyDependenciesOf
+
+			err := stack.filterModules(terragruntOptions)
+			if testCase.expectedErr != nil {
+				assert.Equal(t, testCase.expectedErr, tgerrors.Unwrap(err))
+				return
+			}
+			assert.NoError(t, err)
+
+			var actual, actualAssumed []string
+			for _, module := range stack.Modules {
+				actual = append(actual, module.Path)
+				if module.AssumeAlreadyApplied {
+					actualAssumed = append(actualAssumed, module.Path)
+				}
+			}
+			sort.Strings(actual)
+			sort.Strings(actualAssumed)
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.expectedAssumed, actualAssumed)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package configstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// OutputJSON collects the outputs (terraform output -json) of all the modules in the given stack and prints them as a
+// single JSON object keyed by the path of the modules (relative to the stack) in dependency order. If flatten is true,
+// the outputs are keyed by module_path.output_name instead.
+func (stack *Stack) OutputJSON(command string, terragruntOptions *options.TerragruntOptions, flatten bool) error {
+	stack.setTerraformCommand([]string{command})
+
+	var mutex sync.Mutex
+	outputs := make(map[string]json.RawMessage, len(stack.Modules))
+	handler := func(module TerraformModule, output string, err error) (string, error) {
+		if err != nil {
+			if strings.Contains(output, "no outputs defined") {
+				return "", nil
+			}
+			return output, err
+		}
+		value, err := util.ExtractJSONOutput(output)
+		if err != nil {
+			return output, tgerrors.WithStackTrace(errInvalidJSONOutput{module.Path, err})
+		}
+		// The outputs are also made available to the modules that depend on this one
+		module.TerragruntOptions.OutputCache.Set(module.Path, 0, value)
+		mutex.Lock()
+		defer mutex.Unlock()
+		outputs[module.Path] = value
+		// The outputs are printed all at once when all modules are completed
+		return "", nil
+	}
+
+	startTime := time.Now()
+	results, err := runModulesWithResults(stack.Modules, handler, NormalOrder)
+	if err == nil {
+		var document []byte
+		if document, err = stack.aggregateOutputs(outputs, flatten); err == nil {
+			terragruntOptions.Println(string(document))
+		}
+	}
+	return stack.writeReport(terragruntOptions, command, startTime, results, err)
+}
+
+// Returns the JSON object containing the outputs of all modules in dependency order
+func (stack *Stack) aggregateOutputs(outputs map[string]json.RawMessage, flatten bool) ([]byte, error) {
+	sorted := &Stack{Path: stack.Path, Modules: append([]*TerraformModule(nil), stack.Modules...)}
+	sorted.SortModules()
+
+	keys := make([]string, 0, len(outputs))
+	values := make(map[string]json.RawMessage, len(outputs))
+	for _, module := range sorted.Modules {
+		output, found := outputs[module.Path]
+		if !found {
+			continue
+		}
+		path, err := util.GetPathRelativeTo(module.Path, stack.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !flatten {
+			keys = append(keys, path)
+			values[path] = output
+			continue
+		}
+
+		var moduleOutputs map[string]json.RawMessage
+		if err := json.Unmarshal(output, &moduleOutputs); err != nil {
+			return nil, tgerrors.WithStackTrace(errInvalidJSONOutput{module.Path, err})
+		}
+		names := make([]string, 0, len(moduleOutputs))
+		for name := range moduleOutputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			key := fmt.Sprintf("%s.%s", path, name)
+			keys = append(keys, key)
+			values[key] = moduleOutputs[name]
+		}
+	}
+	return marshalOrderedObject(keys, values)
+}
+
+// Returns an indented JSON object with the keys in the specified order (json.Marshal sorts the keys of maps)
+func marshalOrderedObject(keys []string, values map[string]json.RawMessage) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString("{")
+	for i, key := range keys {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		encodedKey, _ := json.Marshal(key)
+		buffer.WriteString("\n  ")
+		buffer.Write(encodedKey)
+		buffer.WriteString(": ")
+		if err := json.Indent(&buffer, values[key], "  ", "  "); err != nil {
+			return nil, tgerrors.WithStackTrace(err)
+		}
+	}
+	if len(keys) > 0 {
+		buffer.WriteString("\n")
+	}
+	buffer.WriteString("}")
+	return buffer.Bytes(), nil
+}
+
+// Custom error types
+
+type errInvalidJSONOutput struct {
+	path string
+	err  error
+}
+
+func (err errInvalidJSONOutput) Error() string {
+	return fmt.Sprintf("Unable to read the JSON output of module %s: %v", util.GetPathRelativeToWorkingDir(err.path), err.err)
+}
@@ -0,0 +1,93 @@
+package configstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputJSON(t *testing.T) {
+	t.Parallel()
+
+	newModule := func(path, output string, dependencies ...*TerraformModule) *TerraformModule {
+		terragruntOptions := options.NewTerragruntOptionsForTest("/stack/" + path)
+		terragruntOptions.RunTerragrunt = func(terragruntOptions *options.TerragruntOptions) error {
+			_, err := fmt.Fprintln(terragruntOptions.Writer, output)
+			return err
+		}
+		return &TerraformModule{Path: "/stack/" + path, Dependencies: dependencies, TerragruntOptions: terragruntOptions}
+	}
+	vpc := newModule("vpc", `{"id": {"sensitive": false, "type": "string", "value": "vpc-1"}}`)
+	app := newModule("app", `{"url": {"sensitive": false, "type": "string", "value": "http://app"}, "name": {"sensitive": false, "type": "string", "value": "app"}}`, vpc)
+	empty := newModule("empty", `{}`)
+
+	tests := []struct {
+		name     string
+		flatten  bool
+		expected string
+	}{
+		{"Default", false, `{
+  "vpc": {
+    "id": {
+      "sensitive": false,
+      "type": "string",
+      "value": "vpc-1"
+    }
+  },
+  "app": {
+    "url": {
+      "sensitive": false,
+      "type": "string",
+      "value": "http://app"
+    },
+    "name": {
+      "sensitive": false,
+      "type": "string",
+      "value": "app"
+    }
+  },
+  "empty": {}
+}
+`},
+		{"Flatten", true, `{
+  "vpc.id": {
+    "sensitive": false,
+    "type": "string",
+    "value": "vpc-1"
+  },
+  "app.name": {
+    "sensitive": false,
+    "type": "string",
+    "value": "app"
+  },
+  "app.url": {
+    "sensitive": false,
+    "type": "string",
+    "value": "http://app"
+  }
+}
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The stack is run in sequence since the modules and their options are shared between the test cases
+			out := new(closableBuffer)
+			terragruntOptions := options.NewTerragruntOptionsForTest("/stack")
+			terragruntOptions.Writer = out
+			stack := &Stack{Path: "/stack", Modules: []*TerraformModule{app, empty, vpc}}
+			for _, module := range stack.Modules {
+				module.TerragruntOptions.TerraformCliArgs = []string{"-json"}
+			}
+
+			assert.NoError(t, stack.OutputJSON("output", terragruntOptions, tt.flatten))
+			assert.Equal(t, tt.expected, out.String())
+
+			// The outputs are made available to the other output-reading features
+			entry, found := vpc.TerragruntOptions.OutputCache.Get(vpc.Path)
+			assert.True(t, found)
+			assert.JSONEq(t, `{"id": {"sensitive": false, "type": "string", "value": "vpc-1"}}`, string(entry.Outputs))
+		})
+	}
+}
@@ -1,33 +1,52 @@
 package configstack
 
 import (
+	"encoding/json"
 	"fmt"
-	"regexp"
-	"strconv"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/coveooss/terragrunt/v2/options"
 	"github.com/coveooss/terragrunt/v2/shell"
 	"github.com/coveooss/terragrunt/v2/tgerrors"
 	"github.com/coveooss/terragrunt/v2/util"
+	"github.com/fatih/color"
 )
 
 type planSummary struct {
-	Message         string
-	NumberOfChanges int
-	AreChangesKnown bool
+	Message         string `json:"message"`
+	NumberOfChanges int    `json:"number_of_changes"`
+	AreChangesKnown bool   `json:"are_changes_known"`
+	Add             int    `json:"add"`
+	Change          int    `json:"change"`
+	Destroy         int    `json:"destroy"`
+	Replace         int    `json:"replace"`
+
+	ResourceChanges []resourceChange `json:"resource_changes,omitempty"`
 }
 
-// The returned information for each module
-type moduleResult struct {
-	Module      TerraformModule
-	Err         error
-	PlanSummary planSummary
+// The action that will be applied on a single resource
+type resourceChange struct {
+	Address string `json:"address"`
+	Action  string `json:"action"`
 }
 
-var planResultRegex = regexp.MustCompile(`(\d+) to add, (\d+) to change, (\d+) to destroy.`)
+// Possible actions on a resource
+const (
+	actionCreate  = "create"
+	actionUpdate  = "update"
+	actionDelete  = "delete"
+	actionReplace = "replace"
+)
+
+// Name of the file used to save the plan of each module (relative to the terraform working folder)
+const planFileName = "terragrunt.tfplan"
+
+var unknownPlanSummary = planSummary{Message: "Unable to determine the plan status", NumberOfChanges: -1}
 
-func (stack *Stack) planWithSummary(terragruntOptions *options.TerragruntOptions) error {
+func (stack *Stack) planWithSummary(command string, terragruntOptions *options.TerragruntOptions) error {
 	// We override the multi errors creator to use a specialized error type for plan
 	// because error severity in plan is not standard (i.e. exit code 2 is less significant that exit code 1).
 	CreateMultiErrors = func(errs []error) error {
@@ -37,10 +56,30 @@ func (stack *Stack) planWithSummary(terragruntOptions *options.TerragruntOptions
 	detailedExitCode := util.ListContainsElement(terragruntOptions.TerraformCliArgs, "-detailed-exitcode")
 
 	hasChanges := false
+	startTime := time.Now()
 	results := make([]moduleResult, 0, len(stack.Modules))
-	err := runModulesWithHandler(stack.Modules, getResultHandler(detailedExitCode, &results, &hasChanges), NormalOrder)
+	allResults, err := runModulesWithResults(stack.Modules, getResultHandler(detailedExitCode, &results, &hasChanges), NormalOrder)
 	printSummary(terragruntOptions, results)
 
+	// We add the plan summary to the results that will be included in the report
+	for i := range allResults {
+		for _, result := range results {
+			if result.Module.Path == allResults[i].Module.Path {
+				allResults[i].PlanSummary = result.PlanSummary
+			}
+		}
+	}
+	if terragruntOptions.PlanOutputDir != "" {
+		if saveErr := stack.savePlans(command, terragruntOptions, allResults); saveErr != nil {
+			if err != nil {
+				terragruntOptions.Logger.Errorf("Unable to save the plans to %s: %v", terragruntOptions.PlanOutputDir, saveErr)
+			} else {
+				err = saveErr
+			}
+		}
+	}
+	err = stack.writeReport(terragruntOptions, command, startTime, allResults, err)
+
 	// If there is no error, but -detail-exitcode is specified, we return an error with the number of changes.
 	if err == nil && detailedExitCode {
 		knownChanges := 0
@@ -76,7 +115,7 @@ func (stack *Stack) planWithSummary(terragruntOptions *options.TerragruntOptions
 			)
 		}
 
-		if hasChanges {
+		if knownChanges > 0 || hasChanges {
 			return tgerrors.PlanWithChanges{}
 		}
 	}
@@ -96,19 +135,25 @@ func getResultHandler(detailedExitCode bool, results *[]moduleResult, hasChanges
 			err = nil
 		}
 
-		if output != "" {
-			summary := extractSummaryResultFromPlan(output)
-
-			// We add the result to the result list (there is no concurrency problem because it is handled by the running_module)
-			*results = append(*results, moduleResult{module, err, summary})
+		summary := unknownPlanSummary
+		if err == nil {
+			summary = getPlanSummary(module)
 		}
 
+		// We add the result to the result list (there is no concurrency problem because it is handled by the running_module)
+		*results = append(*results, moduleResult{Module: module, Err: err, PlanSummary: &summary})
+
 		return output, err
 	}
 }
 
 // Print a little summary of the plan execution
 func printSummary(terragruntOptions *options.TerragruntOptions, results []moduleResult) {
+	if terragruntOptions.SummaryFormat == options.SummaryJSON {
+		printJSONSummary(terragruntOptions, results)
+		return
+	}
+
 	terragruntOptions.Printf("%s\nSummary:\n", separator)
 
 	var length int
@@ -133,6 +178,67 @@ func printSummary(terragruntOptions *options.TerragruntOptions, results []module
 			errMsg,
 		)
 	}
+
+	if terragruntOptions.SummaryFormat == options.SummaryDetailed {
+		printResourceChanges(terragruntOptions, results)
+	}
+}
+
+// Print the list of resource changes grouped by module
+func printResourceChanges(terragruntOptions *options.TerragruntOptions, results []moduleResult) {
+	for _, result := range results {
+		if len(result.PlanSummary.ResourceChanges) == 0 {
+			continue
+		}
+		terragruntOptions.Printf("\n%s:\n", util.GetPathRelativeToWorkingDir(result.Module.Path))
+		for _, change := range result.PlanSummary.ResourceChanges {
+			terragruntOptions.Println(change.format())
+		}
+	}
+}
+
+// Returns the line describing the change in the detailed summary
+func (change resourceChange) format() string {
+	symbols := map[string]string{
+		actionCreate:  "+",
+		actionUpdate:  "~",
+		actionDelete:  "-",
+		actionReplace: "-/+",
+	}
+
+	line := fmt.Sprintf("    %3s %s (%s)", symbols[change.Action], change.Address, change.Action)
+	if change.Action == actionDelete || change.Action == actionReplace {
+		// Destructive changes are highlighted
+		line = color.HiRedString(line)
+	}
+	return line
+}
+
+// Print the summary as a JSON document
+func printJSONSummary(terragruntOptions *options.TerragruntOptions, results []moduleResult) {
+	type jsonSummary struct {
+		Path        string       `json:"path"`
+		PlanSummary *planSummary `json:"plan_summary"`
+		Error       string       `json:"error,omitempty"`
+	}
+
+	summaries := make([]jsonSummary, len(results))
+	for i, result := range results {
+		summaries[i] = jsonSummary{
+			Path:        util.GetPathRelativeToWorkingDir(result.Module.Path),
+			PlanSummary: result.PlanSummary,
+		}
+		if result.Err != nil {
+			summaries[i].Error = result.Err.Error()
+		}
+	}
+
+	content, err := json.MarshalIndent(summaries, "", "  ")
+	if err != nil {
+		terragruntOptions.Logger.Errorf("Unable to convert the summary to JSON: %v", err)
+		return
+	}
+	terragruntOptions.Println(string(content))
 }
 
 // Check the output message
@@ -149,37 +255,113 @@ func warnAboutMissingDependencies(module TerraformModule, output string) {
 	}
 }
 
-// Parse the output message to extract a summary
-func extractSummaryResultFromPlan(output string) planSummary {
-	noChanges := []string{
-		"Plan: 0 to add, 0 to change, 0 to destroy.", // This was the message returned by terraform 0.11
-		"No changes. Infrastructure is up-to-date.",  // This was the message returned by terraform 0.12
-		"Your infrastructure matches the configuration.",
-		"without changing any real infrastructure.",
+// Set the file where the plan should be saved for each module (unless the user already specified it). The plans are
+// saved in a temporary folder (removed by the returned function) to avoid leaving them in the source folders of the
+// modules. The plans specified by the user are deleted first to ensure that we only collect the plans of this run
+// (i.e. a module skipped by its run conditions does not produce a plan).
+func (stack *Stack) setPlanFile() (cleanup func(), err error) {
+	folder, err := os.MkdirTemp("", "terragrunt-plans-")
+	if err != nil {
+		return nil, tgerrors.WithStackTrace(err)
+	}
+	for i, module := range stack.Modules {
+		if planFile := getPlanFile(module.TerragruntOptions); planFile != "" {
+			if err := os.Remove(planFile); err != nil && !os.IsNotExist(err) {
+				os.RemoveAll(folder)
+				return nil, tgerrors.WithStackTrace(err)
+			}
+			continue
+		}
+		planFile := filepath.Join(folder, fmt.Sprintf("%d.%s", i, planFileName))
+		module.TerragruntOptions.TerraformCliArgs = append(module.TerragruntOptions.TerraformCliArgs, "-out="+planFile)
 	}
-	for _, noChange := range noChanges {
-		if strings.Contains(output, noChange) {
-			return planSummary{"No change", 0, true}
+	return func() { os.RemoveAll(folder) }, nil
+}
+
+// Returns the path of the plan file specified in the terraform arguments
+func getPlanFile(terragruntOptions *options.TerragruntOptions) string {
+	for _, arg := range terragruntOptions.TerraformCliArgs {
+		if strings.HasPrefix(arg, "-out=") {
+			planFile := strings.TrimPrefix(arg, "-out=")
+			if !filepath.IsAbs(planFile) {
+				planFile = filepath.Join(terragruntOptions.WorkingDir, planFile)
+			}
+			return planFile
 		}
 	}
+	return ""
+}
+
+// Read the plan file saved by the module to extract a summary
+func getPlanSummary(module TerraformModule) planSummary {
+	planFile := getPlanFile(module.TerragruntOptions)
+	if !util.FileExists(planFile) {
+		module.TerragruntOptions.Logger.Debugf("Plan file %s not found", planFile)
+		return unknownPlanSummary
+	}
+
+	out, err := shell.NewTFCmd(module.TerragruntOptions).Args("show", "-json", planFile).Output()
+	if err != nil {
+		module.TerragruntOptions.Logger.Warningf("Unable to read the plan file %s: %v\n%s", planFile, err, out)
+		return unknownPlanSummary
+	}
 
-	result := planResultRegex.FindStringSubmatch(output)
-	if len(result) == 0 {
-		return planSummary{"Unable to determine the plan status", -1, false}
+	summary, err := extractSummaryResultFromPlan([]byte(out))
+	if err != nil {
+		module.TerragruntOptions.Logger.Warningf("Unable to parse the plan file %s: %v", planFile, err)
+		return unknownPlanSummary
 	}
+	return summary
+}
+
+// The subset of the terraform show -json format that is required to summarize the plan
+// See https://developer.hashicorp.com/terraform/internals/json-format#plan-representation
+type jsonPlan struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
 
-	// Count the total number of changes
-	sum := 0
-	for _, value := range result[1:] {
-		count, _ := strconv.Atoi(value)
-		sum += count
+// Parse the JSON representation of the plan to extract a summary
+func extractSummaryResultFromPlan(content []byte) (planSummary, error) {
+	var plan jsonPlan
+	if err := json.Unmarshal(content, &plan); err != nil {
+		return unknownPlanSummary, tgerrors.WithStackTrace(err)
 	}
-	if sum != 0 {
-		return planSummary{result[0], sum, true}
+
+	summary := planSummary{AreChangesKnown: true}
+	for _, resource := range plan.ResourceChanges {
+		var action string
+		switch strings.Join(resource.Change.Actions, ",") {
+		case "create":
+			action = actionCreate
+			summary.Add++
+		case "update":
+			action = actionUpdate
+			summary.Change++
+		case "delete":
+			action = actionDelete
+			summary.Destroy++
+		case "delete,create", "create,delete":
+			action = actionReplace
+			summary.Replace++
+		default:
+			// no-op and read actions are not considered as changes
+			continue
+		}
+		summary.ResourceChanges = append(summary.ResourceChanges, resourceChange{resource.Address, action})
 	}
 
-	// Sometimes, terraform returns 0 add, 0 change and 0 destroy. We return a more explicit message
-	return planSummary{"No effective change", 0, true}
+	summary.NumberOfChanges = summary.Add + summary.Change + summary.Destroy + summary.Replace
+	if summary.NumberOfChanges == 0 {
+		summary.Message = "No change"
+	} else {
+		summary.Message = fmt.Sprintf("%d to add, %d to change, %d to destroy, %d to replace.", summary.Add, summary.Change, summary.Destroy, summary.Replace)
+	}
+	return summary, nil
 }
 
 // planMultiError is a specialized version of errMulti type
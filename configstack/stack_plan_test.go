@@ -1,8 +1,13 @@
 package configstack
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/coveooss/terragrunt/v2/options"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -10,46 +15,142 @@ func TestExtractSummaryResultFromPlan(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
-		name       string
-		planOutput string
-		expected   planSummary
+		name      string
+		planJSON  string
+		expected  planSummary
+		expectErr bool
 	}{
 		{
-			name:       "no changes with terraform@0.12",
-			planOutput: "stuff No changes. Infrastructure is up-to-date. stuff",
-			expected:   planSummary{"No change", 0, true},
+			name:     "no resources",
+			planJSON: `{"format_version": "1.2"}`,
+			expected: planSummary{Message: "No change", AreChangesKnown: true},
 		},
 		{
-			name:       "no changes with terraform@0.13",
-			planOutput: "stuff Plan: 0 to add, 0 to change, 0 to destroy. stuff",
-			expected:   planSummary{"No change", 0, true},
+			name: "no changes",
+			planJSON: `{"resource_changes": [
+				{"address": "null_resource.a", "change": {"actions": ["no-op"]}},
+				{"address": "data.null_data_source.b", "change": {"actions": ["read"]}}
+			]}`,
+			expected: planSummary{Message: "No change", AreChangesKnown: true},
 		},
 		{
-			name:       "no changes with terraform@1.0.2",
-			planOutput: "stuff Your infrastructure matches the configuration. stuff",
-			expected:   planSummary{"No change", 0, true},
+			name: "with changes",
+			planJSON: `{"resource_changes": [
+				{"address": "null_resource.a", "change": {"actions": ["create"]}},
+				{"address": "null_resource.b", "change": {"actions": ["create"]}},
+				{"address": "null_resource.c", "change": {"actions": ["update"]}},
+				{"address": "null_resource.d", "change": {"actions": ["delete"]}},
+				{"address": "null_resource.e", "change": {"actions": ["delete", "create"]}},
+				{"address": "null_resource.f", "change": {"actions": ["create", "delete"]}},
+				{"address": "null_resource.g", "change": {"actions": ["no-op"]}}
+			]}`,
+			expected: planSummary{
+				Message:         "2 to add, 1 to change, 1 to destroy, 2 to replace.",
+				NumberOfChanges: 6,
+				AreChangesKnown: true,
+				Add:             2,
+				Change:          1,
+				Destroy:         1,
+				Replace:         2,
+				ResourceChanges: []resourceChange{
+					{"null_resource.a", actionCreate},
+					{"null_resource.b", actionCreate},
+					{"null_resource.c", actionUpdate},
+					{"null_resource.d", actionDelete},
+					{"null_resource.e", actionReplace},
+					{"null_resource.f", actionReplace},
+				},
+			},
 		},
 		{
-			name:       "with changes",
-			planOutput: "stuff 11 to add, 10 to change, 21 to destroy. stuff",
-			expected:   planSummary{"11 to add, 10 to change, 21 to destroy.", 42, true},
-		},
-		{
-			name:       "unknown number of changes",
-			planOutput: "Nobody knows ~~ 👻 ~~ Spooky",
-			expected:   planSummary{"Unable to determine the plan status", -1, false},
-		},
-		{
-			name:       "no changes without a plan",
-			planOutput: "stuff SomethingElse: 0 to add, 0 to change, 0 to destroy. stuff",
-			expected:   planSummary{"No effective change", 0, true},
+			name:      "invalid json",
+			planJSON:  "Nobody knows ~~ 👻 ~~ Spooky",
+			expected:  unknownPlanSummary,
+			expectErr: true,
 		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			summary := extractSummaryResultFromPlan(testCase.planOutput)
+			summary, err := extractSummaryResultFromPlan([]byte(testCase.planJSON))
 			assert.Equal(t, testCase.expected, summary)
+			assert.Equal(t, testCase.expectErr, err != nil)
+		})
+	}
+}
+
+func TestGetPlanFile(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("/stack/module/terragrunt.hcl")
+	assert.Equal(t, "", getPlanFile(terragruntOptions))
+
+	stack := &Stack{Modules: []*TerraformModule{{Path: "/stack/module", TerragruntOptions: terragruntOptions}}}
+	stack.setTerraformCommand([]string{"plan"})
+	cleanup, err := stack.setPlanFile()
+	assert.NoError(t, err)
+	planFile := getPlanFile(terragruntOptions)
+	assert.Equal(t, []string{"plan", "-out=" + planFile}, terragruntOptions.TerraformCliArgs)
+	assert.NotEqual(t, "/stack/module", filepath.Dir(planFile), "The plan should not be saved in the module folder")
+	assert.DirExists(t, filepath.Dir(planFile))
+	cleanup()
+	assert.NoDirExists(t, filepath.Dir(planFile))
+
+	// The plan specified by the user is kept, but the one left by a previous run is deleted
+	userPlan := filepath.Join(t.TempDir(), "my.plan")
+	assert.NoError(t, os.WriteFile(userPlan, []byte("stale plan"), 0644))
+	terragruntOptions.TerraformCliArgs = []string{"plan", "-out=" + userPlan}
+	cleanup, err = stack.setPlanFile()
+	assert.NoError(t, err)
+	defer cleanup()
+	assert.Equal(t, []string{"plan", "-out=" + userPlan}, terragruntOptions.TerraformCliArgs, "The plan file specified by the user should be kept")
+	assert.Equal(t, userPlan, getPlanFile(terragruntOptions))
+	assert.NoFileExists(t, userPlan)
+}
+
+type closableBuffer struct{ bytes.Buffer }
+
+func (closableBuffer) Close() error { return nil }
+
+func TestPrintSummary(t *testing.T) {
+	t.Parallel()
+
+	summary := planSummary{
+		Message:         "1 to add, 0 to change, 1 to destroy, 0 to replace.",
+		NumberOfChanges: 2,
+		AreChangesKnown: true,
+		Add:             1,
+		Destroy:         1,
+		ResourceChanges: []resourceChange{{"null_resource.a", actionCreate}, {"null_resource.b", actionDelete}},
+	}
+	results := []moduleResult{
+		{Module: TerraformModule{Path: "a"}, PlanSummary: &summary},
+		{Module: TerraformModule{Path: "b"}, PlanSummary: &planSummary{Message: "No change", AreChangesKnown: true}},
+	}
+
+	for _, format := range options.SummaryFormats {
+		t.Run(format, func(t *testing.T) {
+			out := new(closableBuffer)
+			terragruntOptions := options.NewTerragruntOptionsForTest("")
+			terragruntOptions.Writer = out
+			terragruntOptions.SummaryFormat = format
+			printSummary(terragruntOptions, results)
+
+			switch format {
+			case options.SummarySimple:
+				assert.Contains(t, out.String(), summary.Message)
+				assert.NotContains(t, out.String(), "null_resource.a")
+			case options.SummaryDetailed:
+				assert.Contains(t, out.String(), summary.Message)
+				assert.Contains(t, out.String(), "+ null_resource.a (create)")
+				assert.Contains(t, out.String(), "- null_resource.b (delete)")
+			case options.SummaryJSON:
+				var actual []map[string]interface{}
+				assert.NoError(t, json.Unmarshal(out.Bytes(), &actual))
+				assert.Len(t, actual, 2)
+				assert.Equal(t, "a", actual[0]["path"])
+				assert.Len(t, actual[0]["plan_summary"].(map[string]interface{})["resource_changes"], 2)
+			}
 		})
 	}
 }
@@ -0,0 +1,32 @@
+package configstack
+
+import (
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// ProtectedModules returns the modules of the stack that are protected against destruction (prevent_destroy)
+func (stack *Stack) ProtectedModules() []*TerraformModule {
+	var protected []*TerraformModule
+	for _, module := range stack.Modules {
+		if module.Config.IsProtected() && !module.AssumeAlreadyApplied {
+			protected = append(protected, module)
+		}
+	}
+	return protected
+}
+
+// SkipProtectedModules excludes the protected modules from the destruction of the stack. Their dependencies are also
+// skipped since they must not be destroyed while a protected module still refers to them.
+func (stack *Stack) SkipProtectedModules(terragruntOptions *options.TerragruntOptions) {
+	skipped := map[string]bool{}
+	for _, module := range stack.ProtectedModules() {
+		addTransitiveDependencies(module, skipped)
+	}
+	for _, module := range stack.Modules {
+		if skipped[module.Path] && !module.AssumeAlreadyApplied {
+			terragruntOptions.Logger.Debugf("Module %s is protected or required by a protected module, it will not be destroyed", util.GetPathRelativeToWorkingDirMax(module.Path, 3))
+			module.AssumeAlreadyApplied = true
+		}
+	}
+}
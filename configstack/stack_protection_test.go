@@ -0,0 +1,31 @@
+package configstack
+
+import (
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/config"
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipProtectedModules(t *testing.T) {
+	t.Parallel()
+
+	protect := true
+	vpc := &TerraformModule{Path: "/stack/vpc"}
+	database := &TerraformModule{Path: "/stack/database", Dependencies: []*TerraformModule{vpc}, Config: config.TerragruntConfig{PreventDestroy: &protect}}
+	app := &TerraformModule{Path: "/stack/app", Dependencies: []*TerraformModule{database, vpc}}
+	cache := &TerraformModule{Path: "/stack/cache", Dependencies: []*TerraformModule{vpc}}
+	stack := &Stack{Path: "/stack", Modules: []*TerraformModule{vpc, database, app, cache}}
+
+	assert.Equal(t, []*TerraformModule{database}, stack.ProtectedModules())
+
+	stack.SkipProtectedModules(options.NewTerragruntOptionsForTest("/stack"))
+
+	// The dependencies of the protected module are also kept since the protected module still refers to them
+	assert.True(t, vpc.AssumeAlreadyApplied)
+	assert.True(t, database.AssumeAlreadyApplied)
+	assert.False(t, app.AssumeAlreadyApplied)
+	assert.False(t, cache.AssumeAlreadyApplied)
+	assert.Empty(t, stack.ProtectedModules())
+}
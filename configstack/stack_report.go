@@ -0,0 +1,261 @@
+package configstack
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/shell"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// The returned information for each module
+type moduleResult struct {
+	Module      TerraformModule
+	Err         error
+	ExitCode    int
+	WorkerID    int
+	StartTime   time.Time
+	EndTime     time.Time
+	PlanSummary *planSummary
+}
+
+// Possible status of a module in the run report
+const (
+	reportSucceeded        = "succeeded"
+	reportFailed           = "failed"
+	reportDependencyFailed = "dependency_failed"
+	reportAssumedApplied   = "assumed_applied"
+	reportSkipped          = "skipped"
+	reportCancelled        = "cancelled"
+	reportRejected         = "rejected"
+)
+
+// Returns the final status of the module execution
+func (result moduleResult) status() string {
+	if result.Err != nil {
+		switch err := tgerrors.Unwrap(result.Err).(type) {
+		case dependencyFinishedWithError:
+			return reportDependencyFailed
+		case errModuleSkipped:
+			return reportSkipped
+		case errModuleCancelled:
+			return reportCancelled
+		case errModuleRejected:
+			if err.RejectedModule != nil {
+				return reportSkipped
+			}
+			return reportRejected
+		}
+		return reportFailed
+	}
+	if result.Module.AssumeAlreadyApplied {
+		return reportAssumedApplied
+	}
+	return reportSucceeded
+}
+
+// Returns the elapsed time of the module execution (0 if the module has not been executed)
+func (result moduleResult) duration() time.Duration {
+	if result.StartTime.IsZero() || result.EndTime.IsZero() {
+		return 0
+	}
+	return result.EndTime.Sub(result.StartTime)
+}
+
+// runReport is the machine-readable report of a stack execution
+type runReport struct {
+	RunID     string         `json:"run_id,omitempty"`
+	Command   string         `json:"command"`
+	Path      string         `json:"path"`
+	StartTime time.Time      `json:"start_time"`
+	EndTime   time.Time      `json:"end_time"`
+	Duration  float64        `json:"duration"`
+	ExitCode  int            `json:"exit_code"`
+	Modules   []moduleReport `json:"modules"`
+}
+
+// moduleReport is the machine-readable report of a single module execution
+type moduleReport struct {
+	Path         string       `json:"path"`
+	Dependencies []string     `json:"dependencies,omitempty"`
+	Status       string       `json:"status"`
+	WorkerID     int          `json:"worker_id,omitempty"`
+	StartTime    *time.Time   `json:"start_time,omitempty"`
+	EndTime      *time.Time   `json:"end_time,omitempty"`
+	Duration     float64      `json:"duration"`
+	ExitCode     int          `json:"exit_code"`
+	Error        string       `json:"error,omitempty"`
+	PlanSummary  *planSummary `json:"plan_summary,omitempty"`
+}
+
+func newRunReport(stack *Stack, command, runID string, startTime time.Time, results []moduleResult, err error) runReport {
+	report := runReport{
+		RunID:     runID,
+		Command:   command,
+		Path:      stack.Path,
+		StartTime: startTime,
+		EndTime:   time.Now(),
+		Modules:   make([]moduleReport, 0, len(results)),
+	}
+	report.Duration = report.EndTime.Sub(report.StartTime).Seconds()
+	if exitCode, errCode := shell.GetExitCode(err); errCode == nil {
+		report.ExitCode = exitCode
+	} else {
+		report.ExitCode = undefinedExitCode
+	}
+
+	for _, result := range results {
+		module := moduleReport{
+			Path:         util.GetPathRelativeToWorkingDir(result.Module.Path),
+			Dependencies: make([]string, 0, len(result.Module.Dependencies)),
+			Status:       result.status(),
+			WorkerID:     result.WorkerID,
+			Duration:     result.duration().Seconds(),
+			ExitCode:     result.ExitCode,
+			PlanSummary:  result.PlanSummary,
+		}
+		for _, dependency := range result.Module.Dependencies {
+			module.Dependencies = append(module.Dependencies, util.GetPathRelativeToWorkingDir(dependency.Path))
+		}
+		if !result.StartTime.IsZero() {
+			startTime := result.StartTime
+			module.StartTime = &startTime
+		}
+		if !result.EndTime.IsZero() {
+			endTime := result.EndTime
+			module.EndTime = &endTime
+		}
+		if result.Err != nil {
+			module.Error = result.Err.Error()
+		}
+		report.Modules = append(report.Modules, module)
+	}
+	return report
+}
+
+// JSON renders the report as a JSON document
+func (report runReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(report, "", "  ")
+}
+
+// JUnit renders the report as a JUnit XML document (each module being a test case)
+func (report runReport) JUnit() ([]byte, error) {
+	suite := junitTestSuite{
+		Name:      report.Command,
+		Timestamp: report.StartTime.Format(time.RFC3339),
+		Time:      report.Duration,
+	}
+	for _, module := range report.Modules {
+		testCase := junitTestCase{
+			Name:      module.Path,
+			ClassName: strings.Fields(report.Command + " terragrunt")[0],
+			Time:      module.Duration,
+		}
+		if module.PlanSummary != nil {
+			testCase.SystemOut = module.PlanSummary.Message
+		}
+		switch module.Status {
+		case reportFailed:
+			suite.Failures++
+			testCase.Failure = &junitMessage{Message: fmt.Sprintf("Exit code %d", module.ExitCode), Content: module.Error}
+		case reportDependencyFailed, reportAssumedApplied, reportSkipped, reportCancelled, reportRejected:
+			suite.Skipped++
+			testCase.Skipped = &junitMessage{Message: module.Status, Content: module.Error}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Tests = len(suite.TestCases)
+
+	result, err := xml.MarshalIndent(junitTestSuites{
+		Name:     report.Command,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), result...), nil
+}
+
+// Write the report to the specified file. If the file has the .xml extension, the report is written
+// in the JUnit format, otherwise, it is written in JSON.
+func (report runReport) write(filename string) (err error) {
+	var content []byte
+	if strings.ToLower(filepath.Ext(filename)) == ".xml" {
+		content, err = report.JUnit()
+	} else {
+		content, err = report.JSON()
+	}
+	if err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	if folder := filepath.Dir(filename); !util.FileExists(folder) {
+		if err = os.MkdirAll(folder, 0755); err != nil {
+			return tgerrors.WithStackTrace(err)
+		}
+	}
+	return tgerrors.WithStackTrace(os.WriteFile(filename, content, 0644))
+}
+
+// Write the report of the execution if the user requested it. The original error is returned unless the
+// report cannot be written.
+func (stack *Stack) writeReport(terragruntOptions *options.TerragruntOptions, command string, startTime time.Time, results []moduleResult, err error) error {
+	if terragruntOptions.ReportFile == "" {
+		return err
+	}
+
+	report := newRunReport(stack, command, terragruntOptions.Env[options.EnvRunID], startTime, results, err)
+	if reportErr := report.write(terragruntOptions.ReportFile); reportErr != nil {
+		if err != nil {
+			terragruntOptions.Logger.Errorf("Unable to write the run report to %s: %v", terragruntOptions.ReportFile, reportErr)
+			return err
+		}
+		return reportErr
+	}
+	terragruntOptions.Logger.Infof("Run report written to %s", terragruntOptions.ReportFile)
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      float64         `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
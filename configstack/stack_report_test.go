@@ -0,0 +1,131 @@
+package configstack
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/config"
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/stretchr/testify/assert"
+)
+
+var errReport = fmt.Errorf("Expected error for module b")
+
+func runModulesForReport(t *testing.T) []moduleResult {
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand("a", nil, new(bool)),
+	}
+	moduleB := &TerraformModule{
+		Path:              "b",
+		Dependencies:      []*TerraformModule{moduleA},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand("b", errReport, new(bool)),
+	}
+	moduleC := &TerraformModule{
+		Path:              "c",
+		Dependencies:      []*TerraformModule{moduleB},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand("c", nil, new(bool)),
+	}
+	moduleD := &TerraformModule{
+		Path:                 "d",
+		Config:               config.TerragruntConfig{},
+		AssumeAlreadyApplied: true,
+		TerragruntOptions:    optionsWithMockTerragruntCommand("d", nil, new(bool)),
+	}
+
+	results, err := runModulesWithResults([]*TerraformModule{moduleA, moduleB, moduleC, moduleD}, nil, NormalOrder)
+	assert.Error(t, err)
+	assert.Len(t, results, 4)
+	return results
+}
+
+func TestModuleResultStatus(t *testing.T) {
+	t.Parallel()
+
+	results := runModulesForReport(t)
+	expected := []string{reportSucceeded, reportFailed, reportDependencyFailed, reportAssumedApplied}
+	for i := range results {
+		assert.Equal(t, expected[i], results[i].status(), results[i].Module.Path)
+	}
+
+	assert.False(t, results[0].StartTime.IsZero())
+	assert.False(t, results[0].EndTime.Before(results[0].StartTime))
+	assert.True(t, results[2].StartTime.IsZero(), "A module that never started should not have a start time")
+	assert.Equal(t, 0.0, results[2].duration().Seconds())
+}
+
+func TestRunReportJSON(t *testing.T) {
+	t.Parallel()
+
+	stack := &Stack{Path: "stack"}
+	results := runModulesForReport(t)
+	results[0].PlanSummary = &planSummary{Message: "No change", AreChangesKnown: true}
+	report := newRunReport(stack, "plan", "run-id", results[0].StartTime, results, errReport)
+
+	content, err := report.JSON()
+	assert.NoError(t, err)
+
+	var actual map[string]interface{}
+	assert.NoError(t, json.Unmarshal(content, &actual))
+	assert.Equal(t, "run-id", actual["run_id"])
+	assert.Equal(t, "plan", actual["command"])
+
+	modules := actual["modules"].([]interface{})
+	assert.Len(t, modules, 4)
+	first := modules[0].(map[string]interface{})
+	assert.Equal(t, "a", first["path"])
+	assert.Equal(t, reportSucceeded, first["status"])
+	assert.Equal(t, "No change", first["plan_summary"].(map[string]interface{})["message"])
+	third := modules[2].(map[string]interface{})
+	assert.Equal(t, []interface{}{"b"}, third["dependencies"])
+	assert.Equal(t, reportDependencyFailed, third["status"])
+	assert.NotContains(t, third, "start_time")
+}
+
+func TestRunReportJUnit(t *testing.T) {
+	t.Parallel()
+
+	stack := &Stack{Path: "stack"}
+	results := runModulesForReport(t)
+	report := newRunReport(stack, "apply -input=false", "", results[0].StartTime, results, errReport)
+
+	content, err := report.JUnit()
+	assert.NoError(t, err)
+
+	var actual junitTestSuites
+	assert.NoError(t, xml.Unmarshal(content, &actual))
+	assert.Equal(t, 4, actual.Tests)
+	assert.Equal(t, 1, actual.Failures)
+	assert.Equal(t, 2, actual.Skipped)
+	assert.Len(t, actual.Suites, 1)
+	assert.Len(t, actual.Suites[0].TestCases, 4)
+	assert.Equal(t, "apply", actual.Suites[0].TestCases[0].ClassName)
+	assert.NotNil(t, actual.Suites[0].TestCases[1].Failure)
+	assert.NotNil(t, actual.Suites[0].TestCases[2].Skipped)
+}
+
+func TestWriteReport(t *testing.T) {
+	t.Parallel()
+
+	folder, err := os.MkdirTemp("", "report")
+	assert.NoError(t, err)
+	defer os.RemoveAll(folder)
+
+	stack := &Stack{Path: "stack"}
+	results := runModulesForReport(t)
+
+	for _, filename := range []string{"sub/report.json", "report.xml"} {
+		terragruntOptions := options.NewTerragruntOptionsForTest("stack")
+		terragruntOptions.ReportFile = filepath.Join(folder, filename)
+		err = stack.writeReport(terragruntOptions, "plan", results[0].StartTime, results, errReport)
+		assert.Equal(t, errReport, err, "The original error should be returned")
+		assert.FileExists(t, terragruntOptions.ReportFile)
+	}
+}
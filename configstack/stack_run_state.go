@@ -0,0 +1,157 @@
+package configstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// runState records the modules that have been successfully processed during a stack execution. It is saved after
+// each module completion, so it is possible to resume a failed execution (see --terragrunt-resume).
+type runState struct {
+	RunID     string   `json:"run_id"`
+	Command   string   `json:"command"`
+	Path      string   `json:"path"`
+	Completed []string `json:"completed"`
+
+	mutex  sync.Mutex
+	stack  *Stack
+	logger func(string, ...interface{})
+}
+
+// The run-state files older than this delay are deleted (the failed runs are not expected to be resumed after that)
+const runStateRetention = 7 * 24 * time.Hour
+
+// Log the command that can be used to resume the run if it failed, or delete the state of the run if it succeeded
+func (state *runState) finish(terragruntOptions *options.TerragruntOptions, err error) {
+	if state == nil {
+		return
+	}
+	if err != nil {
+		terragruntOptions.Logger.Infof("Use --terragrunt-resume %s to resume this run without processing the %d completed module(s) again", state.RunID, len(state.Completed))
+		return
+	}
+	if removeErr := os.Remove(getRunStateFile(state.RunID)); removeErr != nil && !os.IsNotExist(removeErr) {
+		terragruntOptions.Logger.Warningf("Unable to delete the state of run %s: %v", state.RunID, removeErr)
+	}
+}
+
+// Returns the file used to save the state of the specified run
+func getRunStateFile(runID string) string {
+	return util.GetTempDownloadFolder("terragrunt-runs", runID+".json")
+}
+
+// Delete the run-state files that are older than the retention delay
+func pruneRunStates(terragruntOptions *options.TerragruntOptions) {
+	files, _ := filepath.Glob(getRunStateFile("*"))
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil && time.Since(info.ModTime()) > runStateRetention {
+			terragruntOptions.Logger.Debugf("Deleting the expired run state %s", file)
+			os.Remove(file)
+		}
+	}
+}
+
+// EnableRunState records the modules successfully processed by the next executions of the stack in a run-state file.
+// If a run to resume is specified in the options, the modules that have already been completed by that run are
+// considered as already applied and the state of that run is updated instead of creating a new one.
+func (stack *Stack) EnableRunState(command string, terragruntOptions *options.TerragruntOptions) error {
+	state := &runState{
+		RunID:     terragruntOptions.Env[options.EnvRunID],
+		Command:   command,
+		Path:      stack.Path,
+		Completed: []string{},
+		stack:     stack,
+		logger:    terragruntOptions.Logger.Warningf,
+	}
+
+	if terragruntOptions.ResumeRunID != "" {
+		content, err := os.ReadFile(getRunStateFile(terragruntOptions.ResumeRunID))
+		if err != nil {
+			return tgerrors.WithStackTrace(errRunStateNotFound(terragruntOptions.ResumeRunID))
+		}
+		if err := json.Unmarshal(content, state); err != nil {
+			return tgerrors.WithStackTrace(err)
+		}
+		if state.Command != command || state.Path != stack.Path {
+			return tgerrors.WithStackTrace(errRunStateMismatch{state.RunID, state.Command, state.Path})
+		}
+
+		for _, module := range stack.Modules {
+			if path := stack.relativePath(module.Path); util.ListContainsElement(state.Completed, path) {
+				terragruntOptions.Logger.Infof("Module %s has already been completed by run %s", path, state.RunID)
+				module.AssumeAlreadyApplied = true
+			}
+		}
+	}
+
+	pruneRunStates(terragruntOptions)
+	stack.runState = state
+	terragruntOptions.Logger.Debugf("The state of run %s is saved in %s", state.RunID, getRunStateFile(state.RunID))
+	return state.save()
+}
+
+// Returns the handler used to record the completed modules (or nil if the run state is not enabled)
+func (state *runState) handler() ModuleHandler {
+	if state == nil {
+		return nil
+	}
+	return func(module TerraformModule, output string, err error) (string, error) {
+		if err == nil && !module.AssumeAlreadyApplied {
+			if saveErr := state.complete(module.Path); saveErr != nil {
+				state.logger("Unable to save the run state: %v", saveErr)
+			}
+		}
+		return output, err
+	}
+}
+
+// Add the module to the list of completed modules and save the state
+func (state *runState) complete(modulePath string) error {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	if path := state.stack.relativePath(modulePath); !util.ListContainsElement(state.Completed, path) {
+		state.Completed = append(state.Completed, path)
+		sort.Strings(state.Completed)
+	}
+	return state.save()
+}
+
+// Write the run state file (the caller is responsible to hold the lock if there are concurrent accesses)
+func (state *runState) save() error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	filename := getRunStateFile(state.RunID)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	return tgerrors.WithStackTrace(os.WriteFile(filename, content, 0644))
+}
+
+// Custom error types
+
+type errRunStateNotFound string
+
+func (err errRunStateNotFound) Error() string {
+	return fmt.Sprintf("Unable to find the state of run %s (%s)", string(err), getRunStateFile(string(err)))
+}
+
+type errRunStateMismatch struct {
+	RunID   string
+	Command string
+	Path    string
+}
+
+func (err errRunStateMismatch) Error() string {
+	return fmt.Sprintf("Run %s cannot be resumed, it was a %s on %s", err.RunID, err.Command, err.Path)
+}
@@ -0,0 +1,73 @@
+package configstack
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunState(t *testing.T) {
+	t.Setenv("TERRAGRUNT_CACHE", t.TempDir())
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("/stack")
+	terragruntOptions.Env[options.EnvRunID] = "first-run"
+
+	stack := createFilterTestStack()
+	assert.NoError(t, stack.EnableRunState("apply", terragruntOptions))
+	handler := stack.runState.handler()
+	handler(*stack.Modules[0], "", nil)
+	handler(*stack.Modules[1], "", fmt.Errorf("error"))
+	handler(*stack.Modules[3], "", nil)
+	assert.FileExists(t, getRunStateFile("first-run"))
+
+	// Resuming the run should skip the completed modules
+	terragruntOptions.Env[options.EnvRunID] = "second-run"
+	terragruntOptions.ResumeRunID = "first-run"
+	stack = createFilterTestStack()
+	assert.NoError(t, stack.EnableRunState("apply", terragruntOptions))
+	assert.Equal(t, "first-run", stack.runState.RunID)
+	assert.Equal(t, []string{"dns", "network"}, stack.runState.Completed)
+	for _, module := range stack.Modules {
+		assert.Equal(t, module.Path == "/stack/network" || module.Path == "/stack/dns", module.AssumeAlreadyApplied, module.Path)
+	}
+
+	// The run can only be resumed with the same command
+	err := createFilterTestStack().EnableRunState("destroy", terragruntOptions)
+	assert.Equal(t, errRunStateMismatch{"first-run", "apply", "/stack"}, tgerrors.Unwrap(err))
+
+	terragruntOptions.ResumeRunID = "unknown-run"
+	err = createFilterTestStack().EnableRunState("apply", terragruntOptions)
+	assert.Equal(t, errRunStateNotFound("unknown-run"), tgerrors.Unwrap(err))
+
+	// The state is kept while the run fails and deleted once it succeeds
+	terragruntOptions.ResumeRunID = "first-run"
+	stack = createFilterTestStack()
+	assert.NoError(t, stack.EnableRunState("apply", terragruntOptions))
+	stack.runState.finish(terragruntOptions, fmt.Errorf("error"))
+	assert.FileExists(t, getRunStateFile("first-run"))
+	stack.runState.finish(terragruntOptions, nil)
+	assert.NoFileExists(t, getRunStateFile("first-run"))
+}
+
+func TestPruneRunStates(t *testing.T) {
+	t.Setenv("TERRAGRUNT_CACHE", t.TempDir())
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("/stack")
+	for _, runID := range []string{"old-run", "recent-run"} {
+		terragruntOptions.Env[options.EnvRunID] = runID
+		assert.NoError(t, createFilterTestStack().EnableRunState("apply", terragruntOptions))
+	}
+	expired := time.Now().Add(-runStateRetention - time.Hour)
+	assert.NoError(t, os.Chtimes(getRunStateFile("old-run"), expired, expired))
+
+	terragruntOptions.Env[options.EnvRunID] = "new-run"
+	assert.NoError(t, createFilterTestStack().EnableRunState("apply", terragruntOptions))
+	assert.NoFileExists(t, getRunStateFile("old-run"))
+	assert.FileExists(t, getRunStateFile("recent-run"))
+	assert.FileExists(t, getRunStateFile("new-run"))
+}
@@ -0,0 +1,274 @@
+package configstack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coveooss/gotemplate/v3/utils"
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// Name of the file describing the saved plans
+const planManifestFile = "manifest.json"
+
+// planManifest describes the plans saved by plan-all and the state of the stack when they have been created
+type planManifest struct {
+	Command   string               `json:"command"`
+	CreatedAt time.Time            `json:"created_at"`
+	Modules   []planManifestModule `json:"modules"`
+}
+
+// planManifestModule describes the saved plan of a single module (paths are relative to the stack). The modules that
+// have not been planned are also listed (without plan file) so they can be skipped by apply-all.
+type planManifestModule struct {
+	Path         string   `json:"path"`
+	Status       string   `json:"status,omitempty"`
+	Dependencies []string `json:"dependencies,omitempty"`
+	SourceHash   string   `json:"source_hash,omitempty"`
+	PlanFile     string   `json:"plan_file,omitempty"`
+}
+
+// Status of the modules in the plan manifest (the manifests written by the previous versions have no status, they
+// only contain planned modules)
+const (
+	manifestPlanned  = "planned"
+	manifestSkipped  = "skipped"  // Not executed (i.e. run conditions not satisfied) or assumed already applied
+	manifestFailed   = "failed"   // The plan failed
+	manifestExcluded = "excluded" // Excluded by the filters
+)
+
+func (module planManifestModule) isPlanned() bool {
+	return module.Status == "" || module.Status == manifestPlanned
+}
+
+// Save the plan file of each successfully planned module into the output folder and write the manifest
+func (stack *Stack) savePlans(command string, terragruntOptions *options.TerragruntOptions, results []moduleResult) error {
+	outDir := terragruntOptions.PlanOutputDir
+	manifest := planManifest{Command: command, CreatedAt: time.Now()}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+
+	planned := 0
+	for _, result := range results {
+		path := stack.relativePath(result.Module.Path)
+		planFile := getPlanFile(result.Module.TerragruntOptions)
+		switch {
+		case result.Err != nil:
+			manifest.Modules = append(manifest.Modules, planManifestModule{Path: path, Status: manifestFailed})
+			continue
+		case result.Module.AssumeAlreadyApplied || !util.FileExists(planFile):
+			manifest.Modules = append(manifest.Modules, planManifestModule{Path: path, Status: manifestSkipped})
+			continue
+		}
+
+		savedPlanFile := filepath.ToSlash(filepath.Join(path, planFileName))
+		target := filepath.Join(outDir, savedPlanFile)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return tgerrors.WithStackTrace(err)
+		}
+		if err := util.CopyFile(planFile, target); err != nil {
+			return err
+		}
+
+		sourceHash, err := getSourceHash(result.Module)
+		if err != nil {
+			return err
+		}
+		manifest.Modules = append(manifest.Modules, planManifestModule{
+			Path:         path,
+			Status:       manifestPlanned,
+			Dependencies: stack.relativeDependencies(result.Module),
+			SourceHash:   sourceHash,
+			PlanFile:     savedPlanFile,
+		})
+		planned++
+	}
+	for _, module := range stack.excluded {
+		manifest.Modules = append(manifest.Modules, planManifestModule{Path: stack.relativePath(module.Path), Status: manifestExcluded})
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, planManifestFile), content, 0644); err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	terragruntOptions.Logger.Infof("%d plan(s) saved in %s", planned, outDir)
+	return nil
+}
+
+// ApplyPlans applies the plans previously saved by plan-all in the folder specified by the PlanInputDir option.
+// It returns an error if the stack does not match the plans anymore (modules added or removed, or source changed).
+// The modules that have not been planned (skipped, failed or excluded by the filters) are skipped.
+func (stack *Stack) ApplyPlans(command []string, terragruntOptions *options.TerragruntOptions) error {
+	manifest, err := readPlanManifest(terragruntOptions.PlanInputDir)
+	if err != nil {
+		return err
+	}
+	planFiles, err := stack.checkPlanManifest(manifest, terragruntOptions.PlanInputDir)
+	if err != nil {
+		return err
+	}
+
+	stack.setTerraformCommand(command)
+	for _, module := range stack.Modules {
+		if planFile, found := planFiles[module.Path]; found {
+			// The plan file must be the last argument of terraform apply
+			module.TerragruntOptions.TerraformCliArgs = append(module.TerragruntOptions.TerraformCliArgs, planFile)
+		} else if !module.AssumeAlreadyApplied {
+			terragruntOptions.Logger.Infof("Module %s has not been planned, it will not be applied", util.GetPathRelativeToWorkingDirMax(module.Path, 3))
+			module.AssumeAlreadyApplied = true
+		}
+	}
+
+	startTime := time.Now()
+	results, err := runModulesWithResults(stack.Modules, stack.runState.handler(), NormalOrder)
+	stack.runState.finish(terragruntOptions, err)
+	return stack.writeReport(terragruntOptions, strings.Join(command, " "), startTime, results, err)
+}
+
+// Read the manifest saved by plan-all
+func readPlanManifest(folder string) (*planManifest, error) {
+	content, err := os.ReadFile(filepath.Join(folder, planManifestFile))
+	if err != nil {
+		return nil, tgerrors.WithStackTrace(err)
+	}
+	var manifest planManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, tgerrors.WithStackTrace(err)
+	}
+	return &manifest, nil
+}
+
+// Ensure that the saved plans still match the current stack and return the absolute path of the plan file for each
+// planned module
+func (stack *Stack) checkPlanManifest(manifest *planManifest, folder string) (map[string]string, error) {
+	saved := make(map[string]planManifestModule, len(manifest.Modules))
+	for _, module := range manifest.Modules {
+		saved[module.Path] = module
+	}
+
+	var problems []string
+	planFiles := make(map[string]string, len(manifest.Modules))
+	for _, module := range stack.Modules {
+		if module.AssumeAlreadyApplied {
+			continue
+		}
+		path := stack.relativePath(module.Path)
+		savedModule, found := saved[path]
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s has no saved plan", path))
+			continue
+		}
+		delete(saved, path)
+		if !savedModule.isPlanned() {
+			continue
+		}
+
+		sourceHash, err := getSourceHash(*module)
+		if err != nil {
+			return nil, err
+		}
+		if sourceHash != savedModule.SourceHash {
+			problems = append(problems, fmt.Sprintf("%s source has changed since the plan", path))
+		}
+		if dependencies := stack.relativeDependencies(*module); strings.Join(dependencies, ",") != strings.Join(savedModule.Dependencies, ",") {
+			problems = append(problems, fmt.Sprintf("%s dependencies have changed since the plan", path))
+		}
+
+		planFile, err := filepath.Abs(filepath.Join(folder, savedModule.PlanFile))
+		if err != nil {
+			return nil, tgerrors.WithStackTrace(err)
+		}
+		if !util.FileExists(planFile) {
+			problems = append(problems, fmt.Sprintf("%s plan file %s not found", path, savedModule.PlanFile))
+		}
+		planFiles[module.Path] = planFile
+	}
+	for path, savedModule := range saved {
+		if savedModule.isPlanned() {
+			problems = append(problems, fmt.Sprintf("%s is no longer part of the stack", path))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, tgerrors.WithStackTrace(errPlanManifestMismatch(problems))
+	}
+	return planFiles, nil
+}
+
+// Returns the path of the module relative to the stack
+func (stack *Stack) relativePath(path string) string {
+	if relative, err := util.GetPathRelativeTo(path, stack.Path); err == nil {
+		return relative
+	}
+	return path
+}
+
+// Returns the sorted list of dependencies of the module relative to the stack
+func (stack *Stack) relativeDependencies(module TerraformModule) []string {
+	dependencies := make([]string, 0, len(module.Dependencies))
+	for _, dependency := range module.Dependencies {
+		dependencies = append(dependencies, stack.relativePath(dependency.Path))
+	}
+	sort.Strings(dependencies)
+	return dependencies
+}
+
+// Compute a hash of everything that could alter the plan of the module: the terragrunt configuration, the terraform
+// files in the module folder, the terraform source and its files if the source is a local folder.
+func getSourceHash(module TerraformModule) (string, error) {
+	hash := sha256.New()
+	files := []string{module.TerragruntOptions.TerragruntConfigPath}
+	folders := []string{module.Path}
+
+	if module.Config.Terraform != nil && module.Config.Terraform.Source != "" {
+		source := module.Config.Terraform.Source
+		fmt.Fprintln(hash, source)
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(module.Path, source)
+		}
+		if info, err := os.Stat(source); err == nil && info.IsDir() {
+			folders = append(folders, source)
+		}
+	}
+
+	for _, folder := range folders {
+		matches, err := utils.FindFiles(folder, false, false, options.TerraformFilesTemplates...)
+		if err != nil {
+			return "", tgerrors.WithStackTrace(err)
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", tgerrors.WithStackTrace(err)
+		}
+		fmt.Fprintln(hash, filepath.Base(file))
+		hash.Write(content)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Custom error types
+
+type errPlanManifestMismatch []string
+
+func (err errPlanManifestMismatch) Error() string {
+	return fmt.Sprintf("The saved plans do not match the current stack:\n  %s", strings.Join(err, "\n  "))
+}
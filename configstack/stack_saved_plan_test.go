@@ -0,0 +1,132 @@
+package configstack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func createSavedPlanModule(t *testing.T, stackPath, name string, dependencies ...*TerraformModule) *TerraformModule {
+	path := filepath.Join(stackPath, name)
+	assert.NoError(t, os.MkdirAll(path, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(path, options.DefaultConfigName), []byte("# config "+name), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(path, "main.tf"), []byte("# main "+name), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(path, planFileName), []byte("plan "+name), 0644))
+
+	terragruntOptions := options.NewTerragruntOptionsForTest(filepath.Join(path, options.DefaultConfigName))
+	terragruntOptions.TerraformCliArgs = []string{"plan", "-out=" + planFileName}
+	return &TerraformModule{Path: path, Dependencies: dependencies, TerragruntOptions: terragruntOptions}
+}
+
+func TestSavedPlans(t *testing.T) {
+	t.Parallel()
+
+	stackPath, err := os.MkdirTemp("", "stack")
+	assert.NoError(t, err)
+	defer os.RemoveAll(stackPath)
+	outDir := filepath.Join(stackPath, "plans")
+
+	moduleA := createSavedPlanModule(t, stackPath, "a")
+	moduleB := createSavedPlanModule(t, stackPath, "sub/b", moduleA)
+	stack := &Stack{Path: stackPath, Modules: []*TerraformModule{moduleA, moduleB}}
+
+	terragruntOptions := options.NewTerragruntOptionsForTest(stackPath)
+	terragruntOptions.PlanOutputDir = outDir
+	results := []moduleResult{{Module: *moduleA}, {Module: *moduleB}}
+	assert.NoError(t, stack.savePlans("plan", terragruntOptions, results))
+	assert.FileExists(t, filepath.Join(outDir, "a", planFileName))
+	assert.FileExists(t, filepath.Join(outDir, "sub/b", planFileName))
+
+	manifest, err := readPlanManifest(outDir)
+	assert.NoError(t, err)
+	assert.Len(t, manifest.Modules, 2)
+	assert.Equal(t, []string{"a"}, manifest.Modules[1].Dependencies)
+
+	planFiles, err := stack.checkPlanManifest(manifest, outDir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(outDir, "sub/b", planFileName), planFiles[moduleB.Path])
+
+	// A source modification should invalidate the saved plans
+	assert.NoError(t, os.WriteFile(filepath.Join(moduleB.Path, "main.tf"), []byte("# modified"), 0644))
+	_, err = stack.checkPlanManifest(manifest, outDir)
+	assert.Equal(t, errPlanManifestMismatch{"sub/b source has changed since the plan"}, tgerrors.Unwrap(err))
+
+	// Adding or removing modules should also invalidate the saved plans
+	moduleC := createSavedPlanModule(t, stackPath, "c")
+	stack.Modules = []*TerraformModule{moduleA, moduleC}
+	_, err = stack.checkPlanManifest(manifest, outDir)
+	assert.Equal(t, errPlanManifestMismatch{"c has no saved plan", "sub/b is no longer part of the stack"}, tgerrors.Unwrap(err))
+}
+
+func TestSavedPlansWithSkippedModules(t *testing.T) {
+	// Not parallel since plan-all overrides the global multi errors creator
+	defer func(createMultiErrors func([]error) error) { CreateMultiErrors = createMultiErrors }(CreateMultiErrors)
+
+	stackPath := t.TempDir()
+	outDir := filepath.Join(t.TempDir(), "plans")
+
+	var mutex sync.Mutex
+	applied := map[string][]string{}
+	newStack := func() *Stack {
+		newModule := func(name string, dependencies ...*TerraformModule) *TerraformModule {
+			path := filepath.Join(stackPath, name)
+			assert.NoError(t, os.MkdirAll(path, 0755))
+			assert.NoError(t, os.WriteFile(filepath.Join(path, options.DefaultConfigName), []byte("# config "+name), 0644))
+			terragruntOptions := options.NewTerragruntOptionsForTest(filepath.Join(path, options.DefaultConfigName))
+			terragruntOptions.RunTerragrunt = func(terragruntOptions *options.TerragruntOptions) error {
+				switch {
+				case name == "skipped":
+					// The run conditions are not satisfied, nothing is executed
+					return nil
+				case name == "failed":
+					return fmt.Errorf("plan failed")
+				case terragruntOptions.TerraformCliArgs[0] == "plan":
+					return os.WriteFile(getPlanFile(terragruntOptions), []byte("plan "+name), 0644)
+				}
+				mutex.Lock()
+				defer mutex.Unlock()
+				applied[name] = terragruntOptions.TerraformCliArgs
+				return nil
+			}
+			return &TerraformModule{Path: path, Dependencies: dependencies, TerragruntOptions: terragruntOptions}
+		}
+		planned := newModule("planned")
+		modules := []*TerraformModule{planned, newModule("skipped"), newModule("failed"), newModule("app", planned), newModule("excluded")}
+		return &Stack{Path: stackPath, Modules: modules}
+	}
+
+	// The excluded module is removed from the stack during the plan
+	stack := newStack()
+	terragruntOptions := options.NewTerragruntOptionsForTest(stackPath)
+	stack.keepModules(map[string]bool{stack.Modules[0].Path: true, stack.Modules[1].Path: true, stack.Modules[2].Path: true, stack.Modules[3].Path: true}, terragruntOptions)
+	terragruntOptions.PlanOutputDir = outDir
+	assert.Error(t, stack.Plan("plan", terragruntOptions))
+
+	manifest, err := readPlanManifest(outDir)
+	assert.NoError(t, err)
+	statuses := map[string]string{}
+	for _, module := range manifest.Modules {
+		statuses[module.Path] = module.Status
+	}
+	assert.Equal(t, map[string]string{
+		"planned":  manifestPlanned,
+		"skipped":  manifestSkipped,
+		"failed":   manifestFailed,
+		"app":      manifestPlanned,
+		"excluded": manifestExcluded,
+	}, statuses)
+
+	// The modules that have not been planned are skipped by apply-all instead of being considered as changed
+	terragruntOptions = options.NewTerragruntOptionsForTest(stackPath)
+	terragruntOptions.PlanInputDir = outDir
+	assert.NoError(t, newStack().ApplyPlans([]string{"apply", "-input=false"}, terragruntOptions))
+	assert.Len(t, applied, 2)
+	assert.Contains(t, applied, "planned")
+	assert.Equal(t, filepath.Join(outDir, "app", planFileName), applied["app"][len(applied["app"])-1])
+}
@@ -0,0 +1,108 @@
+package configstack
+
+import (
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// Returns the terraform command executed on the module (empty if there is none)
+func (module *TerraformModule) command() string {
+	if module.TerragruntOptions == nil || len(module.TerragruntOptions.TerraformCliArgs) == 0 {
+		return ""
+	}
+	return module.TerragruntOptions.TerraformCliArgs[0]
+}
+
+// Record the duration of the module execution (saved in the timing history at the end of the run). Only the modules that have been successfully
+// executed are recorded since the duration of failed executions is not representative.
+func (module *runningModule) recordTiming(err error) {
+	command := module.Module.command()
+	if err != nil || module.Module.AssumeAlreadyApplied || module.startTime.IsZero() || command == "" {
+		return
+	}
+	util.RecordModuleTiming(module.Module.Path, command, time.Since(module.startTime))
+}
+
+// Returns the estimated duration (in seconds) of each module based on the average duration of its previous
+// executions. The modules that have no history get the average estimation of the other modules. If there is no
+// history at all, all modules get the same estimation.
+func estimateDurations(modules []*TerraformModule) map[string]float64 {
+	estimates := make(map[string]float64, len(modules))
+	if len(modules) == 0 {
+		return estimates
+	}
+
+	history, err := util.LoadTimingHistory()
+	if err != nil {
+		modules[0].TerragruntOptions.Logger.Warningf("Unable to load the timing history: %v", err)
+	}
+
+	var total float64
+	var known []string
+	for _, module := range modules {
+		if timings := history.Get(module.Path, module.command()); timings != nil && len(timings.Durations) > 0 {
+			estimates[module.Path] = timings.Durations.Average().Seconds()
+			total += estimates[module.Path]
+			known = append(known, module.Path)
+		}
+	}
+
+	defaultEstimate := 1.0
+	if len(known) > 0 {
+		defaultEstimate = total / float64(len(known))
+	}
+	for _, module := range modules {
+		if _, exist := estimates[module.Path]; !exist {
+			estimates[module.Path] = defaultEstimate
+		}
+	}
+	return estimates
+}
+
+// CriticalPath returns the chain of dependent modules that has the longest total estimated duration (in execution
+// order) and its duration. Dependencies that are not part of the list of modules are ignored.
+func (modules SimpleTerraformModules) CriticalPath(estimate func(path string) time.Duration) ([]string, time.Duration) {
+	byPath := make(map[string]SimpleTerraformModule, len(modules))
+	for _, module := range modules {
+		byPath[module.Path] = module
+	}
+
+	type result struct {
+		duration time.Duration
+		previous string
+	}
+	results := make(map[string]*result, len(modules))
+	var compute func(string) time.Duration
+	compute = func(path string) time.Duration {
+		if r, exist := results[path]; exist {
+			return r.duration
+		}
+		r := &result{}
+		results[path] = r
+		for _, dependency := range byPath[path].Dependencies {
+			if _, exist := byPath[dependency]; !exist {
+				continue
+			}
+			if duration := compute(dependency); duration > r.duration || r.previous == "" {
+				r.duration, r.previous = duration, dependency
+			}
+		}
+		r.duration += estimate(path)
+		return r.duration
+	}
+
+	var last string
+	var longest time.Duration
+	for _, module := range modules {
+		if duration := compute(module.Path); last == "" || duration > longest {
+			last, longest = module.Path, duration
+		}
+	}
+
+	var path []string
+	for current := last; current != ""; current = results[current].previous {
+		path = append([]string{current}, path...)
+	}
+	return path, longest
+}
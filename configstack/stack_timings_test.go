@@ -0,0 +1,72 @@
+package configstack
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCriticalPath(t *testing.T) {
+	t.Parallel()
+
+	modules := SimpleTerraformModules{
+		{Path: "network"},
+		{Path: "backend", Dependencies: []string{"network"}},
+		{Path: "frontend", Dependencies: []string{"backend", "dns", "external"}},
+		{Path: "dns"},
+		{Path: "database", Dependencies: []string{"network"}},
+	}
+	durations := map[string]time.Duration{
+		"network":  time.Minute,
+		"backend":  2 * time.Minute,
+		"frontend": time.Minute,
+		"dns":      5 * time.Minute,
+		"database": 10 * time.Minute,
+	}
+
+	path, duration := modules.CriticalPath(func(path string) time.Duration { return durations[path] })
+	assert.Equal(t, []string{"network", "database"}, path)
+	assert.Equal(t, 11*time.Minute, duration)
+
+	durations["database"] = time.Minute
+	path, duration = modules.CriticalPath(func(path string) time.Duration { return durations[path] })
+	assert.Equal(t, []string{"dns", "frontend"}, path)
+	assert.Equal(t, 6*time.Minute, duration)
+
+	path, duration = SimpleTerraformModules{}.CriticalPath(func(string) time.Duration { return time.Second })
+	assert.Empty(t, path)
+	assert.Equal(t, time.Duration(0), duration)
+}
+
+func TestEstimateDurations(t *testing.T) {
+	t.Setenv("TERRAGRUNT_CACHE", t.TempDir())
+
+	newModule := func(path string) *TerraformModule {
+		terragruntOptions := options.NewTerragruntOptionsForTest(path)
+		terragruntOptions.TerraformCliArgs = []string{"apply"}
+		return &TerraformModule{Path: path, TerragruntOptions: terragruntOptions}
+	}
+	modules := []*TerraformModule{newModule("/stack/a"), newModule("/stack/b"), newModule("/stack/c")}
+
+	// Without history, all modules have the same estimation
+	assert.Equal(t, map[string]float64{"/stack/a": 1, "/stack/b": 1, "/stack/c": 1}, estimateDurations(modules))
+
+	// The timings recorded by the other tests are discarded
+	assert.NoError(t, util.SaveTimingHistory())
+	if err := os.Remove(util.GetTimingHistoryFile()); !os.IsNotExist(err) {
+		assert.NoError(t, err)
+	}
+
+	util.RecordModuleTiming("/stack/a", "apply", 10*time.Second)
+	util.RecordModuleTiming("/stack/a", "apply", 20*time.Second)
+	util.RecordModuleTiming("/stack/b", "apply", 5*time.Second)
+	util.RecordModuleTiming("/stack/c", "plan", 100*time.Second)
+	assert.NoError(t, util.SaveTimingHistory())
+
+	// The modules without history get the average of the others
+	assert.Equal(t, map[string]float64{"/stack/a": 15, "/stack/b": 5, "/stack/c": 10}, estimateDurations(modules))
+}
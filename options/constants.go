@@ -7,6 +7,19 @@ const (
 	DefaultConfigName        = "terragrunt.hcl"
 )
 
+// Supported formats for the plan-all summary
+const (
+	SummarySimple   = "simple"
+	SummaryDetailed = "detailed"
+	SummaryJSON     = "json"
+)
+
+// Supported formats for the logs
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
 var (
 	configNames []string
 
@@ -18,6 +31,12 @@ var (
 
 	// TerraformFilesTemplates list all files that may contains terraform code (while expanded).
 	TerraformFilesTemplates []string
+
+	// SummaryFormats lists the supported formats for the plan-all summary.
+	SummaryFormats = []string{SummarySimple, SummaryDetailed, SummaryJSON}
+
+	// LogFormats lists the supported formats for the logs.
+	LogFormats = []string{LogFormatText, LogFormatJSON}
 )
 
 func init() {
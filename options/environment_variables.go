@@ -2,30 +2,51 @@ package options
 
 // All environment variables that could be used to configure Terragrunt
 const (
-	EnvCacheFolder         = "TERRAGRUNT_CACHE_FOLDER"          // Used to configure the cache folder (optional, default determined by os temp folder)
-	EnvConfig              = "TERRAGRUNT_CONFIG"                // Used to configure the location of the Terragrunt configuration file (optional, default terragrunt.hcl in the current folder)
-	EnvDebug               = "TERRAGRUNT_DEBUG"                 // Used to enable Terragrunt debug mode
-	EnvFlushDelay          = "TERRAGRUNT_FLUSH_DELAY"           // Used to configure the flush delay on long -all operation (default 60s)
-	EnvLoggingLevel        = "TERRAGRUNT_LOGGING_LEVEL"         // Used to configure the current logging level
-	EnvLoggingFileDir      = "TERRAGRUNT_LOGGING_FILE_DIR"      // Used to configure the directory where (verbose) file logs will be saved
-	EnvLoggingFileLevel    = "TERRAGRUNT_LOGGING_FILE_LEVEL"    // Used to configure the logging level in files
-	EnvSource              = "TERRAGRUNT_SOURCE"                // Used to configure the location of the Terraform source folder (optional, default determined by source in the terragrunt.terraform object
-	EnvSourceUpdate        = "TERRAGRUNT_SOURCE_UPDATE"         // Used to configure the --terragrunt-source-update option (flushes the cache) (optional)
-	EnvTFPath              = "TERRAGRUNT_TFPATH"                // Used to configure the path to the terraform command (optional, default terraform)
-	EnvWorkers             = "TERRAGRUNT_WORKERS"               // Used to configure the maximum number of concurrent workers (optional)
-	EnvApplyTemplate       = "TERRAGRUNT_TEMPLATE"              // Used to configure whether or not go template should be applied on terraform (.tf and .tfvars) file
-	EnvTemplatePatterns    = "TERRAGRUNT_TEMPLATE_PATTERNS"     // Used to configure the extra files (other than .tf) that should be processed by go template
-	EnvBootConfigs         = "TERRAGRUNT_BOOT_CONFIGS"          // Used to set defaults configuration when launching terragrunt
-	EnvPreBootConfigs      = "TERRAGRUNT_PREBOOT_CONFIGS"       // Used to set defaults configuration when launching terragrunt (loaded before user files)
-	EnvIncludeEmptyFolders = "TERRAGRUNT_INCLUDE_EMPTY_FOLDERS" // Used to set the option terragrunt-include-empty-folders
-	EnvAssumedRoleID       = "TERRAGRUNT_ASSUMED_ROLE_ID"       // Used to configure the name of the role assumed by terragrunt
-	EnvPluginsDirectory    = "TERRAGRUNT_PLUGINS_DIRECTORY"     // Used to restrict the plugins download directory
+	EnvCacheFolder           = "TERRAGRUNT_CACHE_FOLDER"            // Used to configure the cache folder (optional, default determined by os temp folder)
+	EnvConfig                = "TERRAGRUNT_CONFIG"                  // Used to configure the location of the Terragrunt configuration file (optional, default terragrunt.hcl in the current folder)
+	EnvDebug                 = "TERRAGRUNT_DEBUG"                   // Used to enable Terragrunt debug mode
+	EnvFlushDelay            = "TERRAGRUNT_FLUSH_DELAY"             // Used to configure the flush delay on long -all operation (default 60s)
+	EnvLoggingLevel          = "TERRAGRUNT_LOGGING_LEVEL"           // Used to configure the current logging level
+	EnvLoggingFileDir        = "TERRAGRUNT_LOGGING_FILE_DIR"        // Used to configure the directory where (verbose) file logs will be saved
+	EnvLoggingFileLevel      = "TERRAGRUNT_LOGGING_FILE_LEVEL"      // Used to configure the logging level in files
+	EnvSource                = "TERRAGRUNT_SOURCE"                  // Used to configure the location of the Terraform source folder (optional, default determined by source in the terragrunt.terraform object
+	EnvSourceUpdate          = "TERRAGRUNT_SOURCE_UPDATE"           // Used to configure the --terragrunt-source-update option (flushes the cache) (optional)
+	EnvTFPath                = "TERRAGRUNT_TFPATH"                  // Used to configure the path to the terraform command (optional, default terraform)
+	EnvWorkers               = "TERRAGRUNT_WORKERS"                 // Used to configure the maximum number of concurrent workers (optional)
+	EnvApplyTemplate         = "TERRAGRUNT_TEMPLATE"                // Used to configure whether or not go template should be applied on terraform (.tf and .tfvars) file
+	EnvTemplatePatterns      = "TERRAGRUNT_TEMPLATE_PATTERNS"       // Used to configure the extra files (other than .tf) that should be processed by go template
+	EnvBootConfigs           = "TERRAGRUNT_BOOT_CONFIGS"            // Used to set defaults configuration when launching terragrunt
+	EnvPreBootConfigs        = "TERRAGRUNT_PREBOOT_CONFIGS"         // Used to set defaults configuration when launching terragrunt (loaded before user files)
+	EnvIncludeEmptyFolders   = "TERRAGRUNT_INCLUDE_EMPTY_FOLDERS"   // Used to set the option terragrunt-include-empty-folders
+	EnvAssumedRoleID         = "TERRAGRUNT_ASSUMED_ROLE_ID"         // Used to configure the name of the role assumed by terragrunt
+	EnvPluginsDirectory      = "TERRAGRUNT_PLUGINS_DIRECTORY"       // Used to restrict the plugins download directory
+	EnvReportFile            = "TERRAGRUNT_REPORT_FILE"             // Used to configure the file where the report of the *-all commands will be written
+	EnvSummaryFormat         = "TERRAGRUNT_SUMMARY"                 // Used to configure the format of the plan-all summary (simple, detailed or json)
+	EnvPlanOutputDir         = "TERRAGRUNT_OUT_DIR"                 // Used to configure the folder where plan-all saves the plans
+	EnvPlanInputDir          = "TERRAGRUNT_PLAN_DIR"                // Used to configure the folder from where apply-all reads the saved plans
+	EnvIncludeDirs           = "TERRAGRUNT_INCLUDE_DIRS"            // Used to configure the glob patterns of the modules included in the *-all commands
+	EnvExcludeDirs           = "TERRAGRUNT_EXCLUDE_DIRS"            // Used to configure the glob patterns of the modules excluded from the *-all commands
+	EnvOnlyDependenciesOf    = "TERRAGRUNT_ONLY_DEPENDENCIES_OF"    // Used to restrict the *-all commands to the specified module and its dependencies
+	EnvChangedSince          = "TERRAGRUNT_CHANGED_SINCE"           // Used to configure the git reference used to select the modules changed by the *-all commands
+	EnvFailFast              = "TERRAGRUNT_FAIL_FAST"               // Used to stop scheduling new modules in *-all commands after the first failure
+	EnvMaxFailures           = "TERRAGRUNT_MAX_FAILURES"            // Used to stop scheduling new modules in *-all commands after the specified number of failures
+	EnvResume                = "TERRAGRUNT_RESUME"                  // Used to configure the run id of the apply-all that should be resumed
+	EnvModuleTimeout         = "TERRAGRUNT_MODULE_TIMEOUT"          // Used to configure the maximum duration of the processing of a module in *-all commands
+	EnvProgress              = "TERRAGRUNT_PROGRESS"                // Used to display a live status of the modules during *-all commands
+	EnvLogFormat             = "TERRAGRUNT_LOG_FORMAT"              // Used to configure the format of the logs (text or json)
+	EnvTraceFile             = "TERRAGRUNT_TRACE_FILE"              // Used to export the trace spans to a JSON file
+	EnvEventWebhook          = "TERRAGRUNT_EVENT_WEBHOOK"           // Used to configure the URL where the events of the *-all commands are posted
+	EnvAllowDestroyProtected = "TERRAGRUNT_ALLOW_DESTROY_PROTECTED" // Used to allow the destruction of the modules protected by prevent_destroy
+	EnvApproveEach           = "TERRAGRUNT_APPROVE_EACH"            // Used to ask the approval of the plan of each module in apply-all
+	EnvApprovalHandler       = "TERRAGRUNT_APPROVAL"                // Used to configure the program or the URL used to approve the commands in a non interactive context
+	EnvApprovalTimeout       = "TERRAGRUNT_APPROVAL_TIMEOUT"        // Used to configure the maximum duration of a call to the approval handler
 )
 
 // All environment variables that are published during Terragrunt execution to share current context during shell execution
 const (
 	EnvArgs            = "TERRAGRUNT_ARGS"             // Used to publish the supplied arguments to the Terragrunt command
 	EnvCommand         = "TERRAGRUNT_COMMAND"          // Used to publish the current Terragrunt command
+	EnvEvent           = "TERRAGRUNT_EVENT"            // Used to publish the type of the event to the event hooks (on_event)
 	EnvExtraCommand    = "TERRAGRUNT_EXTRA_COMMAND"    // Used to publish the name of the actual running command
 	EnvLaunchFolder    = "TERRAGRUNT_LAUNCH_FOLDER"    // Used to publish the launch folder from where the Terragrunt operation has been launched
 	EnvRunID           = "TERRAGRUNT_RUN_ID"           // Used to publish the current run id, this is unique to each Terragrunt execution, but can be used to link -all operations
@@ -0,0 +1,38 @@
+package options
+
+import "time"
+
+// Types of the events notified during the execution of a stack
+const (
+	EventStackStarted   = "stack-started"
+	EventModuleStarted  = "module-started"
+	EventModuleFinished = "module-finished"
+	EventStackFinished  = "stack-finished"
+)
+
+// EventTypes lists the types of the events notified during the execution of a stack
+var EventTypes = []string{EventStackStarted, EventModuleStarted, EventModuleFinished, EventStackFinished}
+
+// Event describes a step of the execution of a stack. It is sent as JSON to the event hooks (on_event) and webhooks.
+type Event struct {
+	Type      string    `json:"type"`
+	RunID     string    `json:"run_id,omitempty"`
+	Command   string    `json:"command,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Path      string    `json:"path"`               // The path of the module (module events) or of the stack (stack events)
+	Modules   []string  `json:"modules,omitempty"`  // The modules of the stack (stack events)
+	Worker    int       `json:"worker,omitempty"`   // The worker processing the module (module events)
+	Status    string    `json:"status,omitempty"`   // The final status of the module or the stack (finished events)
+	Error     string    `json:"error,omitempty"`    // The error message if the module or the stack failed
+	Failed    []string  `json:"failed,omitempty"`   // The modules that failed (stack-finished)
+	Duration  float64   `json:"duration,omitempty"` // The duration in seconds (finished events)
+}
+
+// EventListener is a function that is called for each event of the stack executions (see AddEventListener)
+type EventListener func(Event)
+
+// AddEventListener registers a function that is called for each event of the stack executions. The listeners are
+// called in order on a separate goroutine and all events are delivered before the end of the stack execution.
+func (terragruntOptions *TerragruntOptions) AddEventListener(listener EventListener) {
+	terragruntOptions.EventListeners = append(terragruntOptions.EventListeners, listener)
+}
@@ -0,0 +1,186 @@
+package options
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coveooss/multilogger"
+	"github.com/sirupsen/logrus"
+)
+
+// Fields added to the logs when the JSON log format is enabled
+const (
+	LogFieldModule       = "module"
+	LogFieldWorker       = "worker"
+	LogFieldRunID        = "run_id"
+	LogFieldHook         = "hook"
+	LogFieldExtraCommand = "extra_command"
+	LogFieldStream       = "stream"
+)
+
+// Values of the stream field
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// The field used by multilogger to store the name of the logger (we use our own module field instead)
+const loggerNameField = "module-field"
+
+var ansiEscapeSequence = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+
+// JSONLogs returns true if the logs are rendered as JSON objects (see LogFormat)
+func (terragruntOptions *TerragruntOptions) JSONLogs() bool {
+	return terragruntOptions.LogFormat == LogFormatJSON
+}
+
+// EnableJSONLogs renders the logs as JSON objects (one per line) and replaces the writers by writers that log each line
+// of output as a JSON object with the same fields as the logs.
+func (terragruntOptions *TerragruntOptions) EnableJSONLogs(stdout, stderr io.Writer) {
+	terragruntOptions.LogFormat = LogFormatJSON
+	logger := terragruntOptions.Logger.SetColor(false).SetFormatter(NewJSONFormatter()).SetOut(stderr)
+	terragruntOptions.Logger = logger.WithField(LogFieldRunID, terragruntOptions.Env[EnvRunID])
+	terragruntOptions.Writer = &jsonLogWriter{logger: terragruntOptions.Logger, stream: StreamStdout, out: stdout}
+	terragruntOptions.ErrWriter = &jsonLogWriter{logger: terragruntOptions.Logger, stream: StreamStderr, out: stderr}
+}
+
+// SetLogger replaces the logger. With the JSON log format, the writers are also replaced to include the fields of the
+// new logger in each line of output.
+func (terragruntOptions *TerragruntOptions) SetLogger(logger *multilogger.Logger) {
+	terragruntOptions.Logger = logger
+	if writer, ok := terragruntOptions.Writer.(*jsonLogWriter); ok {
+		terragruntOptions.Writer = writer.copy(logger, writer.capture)
+	}
+	if writer, ok := terragruntOptions.ErrWriter.(*jsonLogWriter); ok {
+		terragruntOptions.ErrWriter = writer.copy(logger, writer.capture)
+	}
+}
+
+// WithLogFields returns a copy of the options that adds the supplied fields to the logs and the output. The options are
+// returned as is if the JSON log format is not enabled.
+func (terragruntOptions *TerragruntOptions) WithLogFields(fields logrus.Fields) *TerragruntOptions {
+	if !terragruntOptions.JSONLogs() {
+		return terragruntOptions
+	}
+	newOptions := *terragruntOptions
+	newOptions.SetLogger(terragruntOptions.Logger.WithFields(fields))
+	return &newOptions
+}
+
+// CaptureOutput sends a copy of the raw output to the supplied writer (only relevant with the JSON log format since the
+// output is directly logged).
+func (terragruntOptions *TerragruntOptions) CaptureOutput(capture io.Writer) {
+	if writer, ok := terragruntOptions.Writer.(*jsonLogWriter); ok {
+		terragruntOptions.Writer = writer.copy(writer.logger, capture)
+	}
+	if writer, ok := terragruntOptions.ErrWriter.(*jsonLogWriter); ok {
+		terragruntOptions.ErrWriter = writer.copy(writer.logger, capture)
+	}
+}
+
+// FlushOutput logs the last line of output if it has not been terminated by a new line (only relevant with the JSON
+// log format).
+func (terragruntOptions *TerragruntOptions) FlushOutput() {
+	for _, writer := range []io.Writer{terragruntOptions.Writer, terragruntOptions.ErrWriter} {
+		if writer, ok := writer.(*jsonLogWriter); ok {
+			writer.Close()
+		}
+	}
+}
+
+// NewJSONFormatter returns the formatter used to render the logs as JSON objects
+func NewJSONFormatter() logrus.Formatter {
+	return jsonFormatter{&logrus.JSONFormatter{
+		TimestampFormat: time.RFC3339Nano,
+		FieldMap:        logrus.FieldMap{logrus.FieldKeyTime: "timestamp", logrus.FieldKeyMsg: "message"},
+	}}
+}
+
+type jsonFormatter struct {
+	*logrus.JSONFormatter
+}
+
+func (formatter jsonFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data)+1)
+	for key, value := range entry.Data {
+		if key != loggerNameField {
+			data[key] = value
+		}
+	}
+	if _, exist := data[LogFieldStream]; !exist {
+		// Unless specified otherwise, the logs are written on stderr
+		data[LogFieldStream] = StreamStderr
+	}
+
+	formatted := entry.Dup()
+	formatted.Data = data
+	formatted.Level = entry.Level
+	formatted.Message = strings.TrimSpace(ansiEscapeSequence.ReplaceAllString(entry.Message, ""))
+	if formatted.Time.IsZero() {
+		formatted.Time = time.Now()
+	}
+	return formatter.JSONFormatter.Format(formatted)
+}
+
+// All JSON writers share the same lock to ensure that the lines are not interleaved
+var jsonLogMutex sync.Mutex
+
+// jsonLogWriter logs each line written to it as a JSON object including the fields of the logger. The lines are logged
+// independently of the logging level since they are the output of the commands.
+type jsonLogWriter struct {
+	logger    *multilogger.Logger
+	stream    string
+	out       io.Writer
+	capture   io.Writer // Receives a copy of the raw output if set
+	remaining string
+}
+
+func (writer *jsonLogWriter) copy(logger *multilogger.Logger, capture io.Writer) *jsonLogWriter {
+	return &jsonLogWriter{logger: logger, stream: writer.stream, out: writer.out, capture: capture}
+}
+
+func (writer *jsonLogWriter) Write(p []byte) (int, error) {
+	jsonLogMutex.Lock()
+	defer jsonLogMutex.Unlock()
+
+	if writer.capture != nil {
+		writer.capture.Write(p)
+	}
+	lines := strings.Split(writer.remaining+string(p), "\n")
+	writer.remaining = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		if err := writer.log(line); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close logs the remaining incomplete line (the underlying writer is not closed)
+func (writer *jsonLogWriter) Close() error {
+	jsonLogMutex.Lock()
+	defer jsonLogMutex.Unlock()
+
+	line := writer.remaining
+	writer.remaining = ""
+	return writer.log(line)
+}
+
+func (writer *jsonLogWriter) log(line string) error {
+	if strings.TrimSpace(ansiEscapeSequence.ReplaceAllString(line, "")) == "" {
+		// Empty lines are only used for presentation
+		return nil
+	}
+	entry := writer.logger.Entry.WithField(LogFieldStream, writer.stream)
+	entry.Level = logrus.InfoLevel
+	entry.Message = line
+	formatted, err := NewJSONFormatter().Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = writer.out.Write(formatted)
+	return err
+}
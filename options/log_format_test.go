@@ -0,0 +1,53 @@
+package options
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONLogs(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr, captured bytes.Buffer
+	terragruntOptions := NewTerragruntOptionsForTest("/tmp/terragrunt.hcl")
+	terragruntOptions.Env[EnvRunID] = "run"
+	terragruntOptions.EnableJSONLogs(&stdout, &stderr)
+	terragruntOptions.SetLogger(terragruntOptions.Logger.WithField(LogFieldModule, "module"))
+	terragruntOptions.CaptureOutput(&captured)
+
+	hookOptions := terragruntOptions.WithLogFields(logrus.Fields{LogFieldHook: "hook"})
+	fmt.Fprint(terragruntOptions.Writer, "line 1\n\x1b[1mline 2\x1b[0m\n\nincomplete")
+	fmt.Fprintln(hookOptions.ErrWriter, "hook line")
+	terragruntOptions.Logger.Warning("message")
+	assert.Len(t, lines(stdout.String()), 2, "The incomplete line must not be logged until the output is flushed")
+	terragruntOptions.FlushOutput()
+
+	decode := func(line string) (result map[string]interface{}) {
+		assert.NoError(t, json.Unmarshal([]byte(line), &result))
+		assert.NotEmpty(t, result["timestamp"])
+		delete(result, "timestamp")
+		return
+	}
+	output := lines(stdout.String())
+	assert.Len(t, output, 3)
+	assert.Equal(t, map[string]interface{}{"level": "info", "message": "line 1", "module": "module", "run_id": "run", "stream": "stdout"}, decode(output[0]))
+	assert.Equal(t, "line 2", decode(output[1])["message"])
+	assert.Equal(t, "incomplete", decode(output[2])["message"])
+
+	errors := lines(stderr.String())
+	assert.Len(t, errors, 2)
+	assert.Equal(t, map[string]interface{}{"level": "info", "message": "hook line", "module": "module", "run_id": "run", "stream": "stderr", "hook": "hook"}, decode(errors[0]))
+	assert.Equal(t, map[string]interface{}{"level": "warning", "message": "message", "module": "module", "run_id": "run", "stream": "stderr"}, decode(errors[1]))
+
+	assert.Equal(t, "line 1\n\x1b[1mline 2\x1b[0m\n\nincompletehook line\n", captured.String())
+}
+
+func lines(output string) []string {
+	return strings.Split(strings.TrimSpace(output), "\n")
+}
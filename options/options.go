@@ -1,6 +1,7 @@
 package options
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -32,6 +33,9 @@ type TerragruntOptions struct {
 	// The approval handler that will be used in a non interactive context
 	ApprovalHandler string
 
+	// ApprovalTimeout is the maximum duration of a call to the approval handler (0 means the default timeout)
+	ApprovalTimeout time.Duration
+
 	// CLI args that are intended for Terraform (i.e. all the CLI args except the --terragrunt ones)
 	TerraformCliArgs []string
 
@@ -107,6 +111,79 @@ type TerragruntOptions struct {
 
 	// PluginsDirectory is used to restrict plugin downloads to a specific directory (no remote plugins will be downloaded if this is set)
 	PluginsDirectory string
+
+	// ReportFile is used to write a machine-readable report of the *-all commands (JUnit if the extension is .xml, JSON otherwise)
+	ReportFile string
+
+	// SummaryFormat is used to configure the format of the plan-all summary (see SummaryFormats)
+	SummaryFormat string
+
+	// PlanOutputDir is used by plan-all to save the plan of each module with a manifest describing the stack
+	PlanOutputDir string
+
+	// PlanInputDir is used by apply-all to apply the plans previously saved by plan-all
+	PlanInputDir string
+
+	// IncludeDirs is a list of glob patterns used to select the modules that should be included in the *-all commands
+	IncludeDirs []string
+
+	// ExcludeDirs is a list of glob patterns used to select the modules that should be excluded from the *-all commands
+	ExcludeDirs []string
+
+	// OnlyDependenciesOf restricts the *-all commands to the specified module and its dependencies
+	OnlyDependenciesOf string
+
+	// ChangedSince restricts the *-all commands to the modules affected by the files changed since the git reference
+	ChangedSince string
+
+	// FailFast stops scheduling new modules in *-all commands after the first failure (running modules are allowed to finish)
+	FailFast bool
+
+	// MaxFailures stops scheduling new modules in *-all commands once the number of failures is reached (0 means no limit)
+	MaxFailures int
+
+	// ResumeRunID is the identifier of a previous apply-all run that should be resumed (the modules completed by that run are skipped)
+	ResumeRunID string
+
+	// ModuleTimeout is the maximum duration of the processing of a module in *-all commands (0 means no limit)
+	ModuleTimeout time.Duration
+
+	// Deadline is the time at which all commands started for the current module are stopped (zero means no deadline)
+	Deadline time.Time
+
+	// Progress displays a live status of the modules during *-all commands (if the output is a terminal)
+	Progress bool
+
+	// LogFormat is used to configure the format of the logs (see LogFormats)
+	LogFormat string
+
+	// TraceFile is used to export the trace spans to a JSON file (in addition to the OTEL_* environment variables)
+	TraceFile string
+
+	// TraceContext holds the current trace span (see StartSpan)
+	TraceContext context.Context
+
+	// EventWebhook is used to post the events of the stack executions to the specified URL
+	EventWebhook string
+
+	// EventListeners are called for each event of the stack executions (see AddEventListener)
+	EventListeners []EventListener
+
+	// DependencyOutputs contains the outputs of the dependency blocks of the current config (dependency.<name>.outputs)
+	DependencyOutputs map[string]interface{}
+
+	// IgnoreDependencyOutputs indicates that the outputs of the dependency blocks should not be read while parsing the
+	// config (i.e. when only the dependencies between the modules are required)
+	IgnoreDependencyOutputs bool
+
+	// AllowDestroyProtected allows the destruction of the modules protected by prevent_destroy
+	AllowDestroyProtected bool
+
+	// ApproveEach asks the user to approve the plan of each module before it is applied by apply-all
+	ApproveEach bool
+
+	// OutputCache keeps the outputs of the modules read during the current run (shared by all the clones of the options)
+	OutputCache *OutputCache
 }
 
 // NewTerragruntOptions creates a new TerragruntOptions object with reasonable defaults for real usage
@@ -144,6 +221,11 @@ func NewTerragruntOptions(terragruntConfigPath string) *TerragruntOptions {
 		RunTerragrunt: func(terragruntOptions *TerragruntOptions) error {
 			return tgerrors.WithStackTrace(ErrRunTerragruntCommandNotSet)
 		},
+		OutputCache:                NewOutputCache(),
+		SummaryFormat:              SummarySimple,
+		LogFormat:                  LogFormatText,
+		IncludeDirs:                []string{},
+		ExcludeDirs:                []string{},
 		TemplateAdditionalPatterns: []string{},
 		BootConfigurationPaths:     []string{},
 		PreBootConfigurationPaths:  []string{},
@@ -221,9 +303,14 @@ func (terragruntOptions TerragruntOptions) Clone(terragruntConfigPath string) *T
 	newOptions.WorkingDir = filepath.Dir(terragruntConfigPath)
 	newOptions.Env = make(map[string]string, len(terragruntOptions.Env))
 	newOptions.Variables = make(map[string]Variable, len(terragruntOptions.Variables))
+	newOptions.DependencyOutputs = nil
 
 	if newLoggerName := util.GetPathRelativeToWorkingDir(newOptions.WorkingDir); newLoggerName != "." {
-		newOptions.Logger = terragruntOptions.Logger.Child(util.GetPathRelativeToWorkingDir(newOptions.WorkingDir))
+		logger := terragruntOptions.Logger.Child(newLoggerName)
+		if terragruntOptions.JSONLogs() {
+			logger = logger.WithField(LogFieldModule, newLoggerName)
+		}
+		newOptions.SetLogger(logger)
 	}
 
 	// We create a distinct map for the environment variables
@@ -272,6 +359,9 @@ func (terragruntOptions TerragruntOptions) GetContext() (result collections.IDic
 	context["TerragruntConfigPath"] = terragruntOptions.TerragruntConfigPath
 	context["WorkingDir"] = terragruntOptions.WorkingDir
 	result.Set("TerragruntOptions", context)
+	if len(terragruntOptions.DependencyOutputs) > 0 {
+		result.Set("dependency", terragruntOptions.DependencyOutputs)
+	}
 	return
 }
 
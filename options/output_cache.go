@@ -0,0 +1,112 @@
+package options
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/coveooss/terragrunt/v2/util"
+)
+
+// OutputCache keeps the outputs of the modules (as returned by terraform output -json) that have been read during the
+// current run, so that the modules sharing a dependency do not have to read its outputs again. The entries are keyed by
+// module path and state serial, an entry is only replaced by the outputs of a more recent state.
+type OutputCache struct {
+	mutex   sync.Mutex
+	entries map[string]OutputCacheEntry
+	loading map[string]*outputLoad
+}
+
+// outputLoad is a read of the outputs of a module that is in progress, the concurrent reads wait for its result
+type outputLoad struct {
+	done    chan struct{}
+	outputs json.RawMessage
+	err     error
+}
+
+// OutputCacheEntry contains the outputs of a module at a given state serial (0 if the serial is unknown)
+type OutputCacheEntry struct {
+	Serial  int64
+	Outputs json.RawMessage
+}
+
+// NewOutputCache creates an empty output cache
+func NewOutputCache() *OutputCache {
+	return &OutputCache{entries: map[string]OutputCacheEntry{}, loading: map[string]*outputLoad{}}
+}
+
+// Get returns the cached outputs of the module
+func (cache *OutputCache) Get(modulePath string) (OutputCacheEntry, bool) {
+	if cache == nil {
+		return OutputCacheEntry{}, false
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	entry, found := cache.entries[util.CleanPath(modulePath)]
+	return entry, found
+}
+
+// GetOrLoad returns the cached outputs of the module or reads them with the load function and saves them in the cache
+// (with an unknown serial). The concurrent calls for the same module wait for a single read and share its result. It
+// returns true if the outputs have not been read by the current call.
+func (cache *OutputCache) GetOrLoad(modulePath string, load func() (json.RawMessage, error)) (json.RawMessage, bool, error) {
+	if cache == nil {
+		outputs, err := load()
+		return outputs, false, err
+	}
+	modulePath = util.CleanPath(modulePath)
+
+	cache.mutex.Lock()
+	if entry, found := cache.entries[modulePath]; found {
+		cache.mutex.Unlock()
+		return entry.Outputs, true, nil
+	}
+	if current := cache.loading[modulePath]; current != nil {
+		cache.mutex.Unlock()
+		<-current.done
+		return current.outputs, true, current.err
+	}
+	current := &outputLoad{done: make(chan struct{})}
+	cache.loading[modulePath] = current
+	cache.mutex.Unlock()
+
+	current.outputs, current.err = load()
+
+	cache.mutex.Lock()
+	// The outputs are not saved if the cache has been invalidated while they were read (they may be outdated)
+	if cache.loading[modulePath] == current {
+		delete(cache.loading, modulePath)
+		if _, found := cache.entries[modulePath]; current.err == nil && !found {
+			cache.entries[modulePath] = OutputCacheEntry{Outputs: current.outputs}
+		}
+	}
+	cache.mutex.Unlock()
+	close(current.done)
+	return current.outputs, false, current.err
+}
+
+// Set saves the outputs of the module, unless the cache already contains the outputs of a more recent state. It returns
+// true if the entry has been saved.
+func (cache *OutputCache) Set(modulePath string, serial int64, outputs json.RawMessage) bool {
+	if cache == nil {
+		return false
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	modulePath = util.CleanPath(modulePath)
+	if entry, found := cache.entries[modulePath]; found && entry.Serial > serial {
+		return false
+	}
+	cache.entries[modulePath] = OutputCacheEntry{Serial: serial, Outputs: outputs}
+	return true
+}
+
+// Invalidate removes the outputs of the module from the cache (i.e. when its state has been modified)
+func (cache *OutputCache) Invalidate(modulePath string) {
+	if cache == nil {
+		return
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	delete(cache.entries, util.CleanPath(modulePath))
+	delete(cache.loading, util.CleanPath(modulePath))
+}
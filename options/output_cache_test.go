@@ -0,0 +1,90 @@
+package options
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputCache(t *testing.T) {
+	t.Parallel()
+
+	cache := NewOutputCache()
+	_, found := cache.Get("/stack/vpc")
+	assert.False(t, found)
+
+	assert.True(t, cache.Set("/stack/vpc", 0, []byte(`{"id": {"value": "unknown serial"}}`)))
+	assert.True(t, cache.Set("/stack/app/../vpc", 2, []byte(`{"id": {"value": "serial 2"}}`)))
+	assert.False(t, cache.Set("/stack/vpc", 1, []byte(`{"id": {"value": "serial 1"}}`)), "Older states should not replace the cached outputs")
+
+	entry, found := cache.Get("/stack/vpc/")
+	assert.True(t, found)
+	assert.Equal(t, OutputCacheEntry{2, []byte(`{"id": {"value": "serial 2"}}`)}, entry)
+
+	cache.Invalidate("/stack/vpc")
+	_, found = cache.Get("/stack/vpc")
+	assert.False(t, found)
+
+	var disabled *OutputCache
+	assert.False(t, disabled.Set("/stack/vpc", 1, nil))
+	_, found = disabled.Get("/stack/vpc")
+	assert.False(t, found)
+}
+
+func TestOutputCacheGetOrLoad(t *testing.T) {
+	t.Parallel()
+
+	cache := NewOutputCache()
+	var loads int32
+	load := func() (json.RawMessage, error) {
+		atomic.AddInt32(&loads, 1)
+		time.Sleep(50 * time.Millisecond)
+		return []byte(`{"id": {"value": "vpc-1"}}`), nil
+	}
+
+	// The concurrent reads of the same module wait for a single load
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outputs, _, err := cache.GetOrLoad("/stack/vpc", load)
+			assert.NoError(t, err)
+			assert.Equal(t, json.RawMessage(`{"id": {"value": "vpc-1"}}`), outputs)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+
+	_, cached, err := cache.GetOrLoad("/stack/vpc/", load)
+	assert.NoError(t, err)
+	assert.True(t, cached)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+
+	// The errors are not cached
+	_, cached, err = cache.GetOrLoad("/stack/app", func() (json.RawMessage, error) { return nil, fmt.Errorf("no state") })
+	assert.Error(t, err)
+	assert.False(t, cached)
+	_, found := cache.Get("/stack/app")
+	assert.False(t, found)
+
+	// The outputs read while the cache is invalidated are not saved
+	_, _, err = cache.GetOrLoad("/stack/db", func() (json.RawMessage, error) {
+		cache.Invalidate("/stack/db")
+		return []byte(`{}`), nil
+	})
+	assert.NoError(t, err)
+	_, found = cache.Get("/stack/db")
+	assert.False(t, found)
+
+	var disabled *OutputCache
+	outputs, cached, err := disabled.GetOrLoad("/stack/vpc", load)
+	assert.NoError(t, err)
+	assert.False(t, cached)
+	assert.NotNil(t, outputs)
+}
@@ -0,0 +1,34 @@
+package options
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// TracerName is the name of the tracer used to create the spans of terragrunt
+const TracerName = "github.com/coveooss/terragrunt"
+
+// StartSpan starts a trace span as a child of the current span of the options. The new span becomes the current span
+// until the returned function is called to end it with the final status of the operation. The spans are discarded if
+// tracing is not enabled (see cli.initTracing).
+func (terragruntOptions *TerragruntOptions) StartSpan(name string, attributes ...attribute.KeyValue) (end func(error)) {
+	previous, parent := terragruntOptions.TraceContext, terragruntOptions.TraceContext
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, span := otel.Tracer(TracerName).Start(parent, name)
+	span.SetAttributes(attributes...)
+	terragruntOptions.TraceContext = ctx
+
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		terragruntOptions.TraceContext = previous
+	}
+}
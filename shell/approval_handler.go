@@ -2,20 +2,49 @@ package shell
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
 	goErrors "errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/coveooss/terragrunt/v2/util"
 )
 
 var sharedMutex = sync.Mutex{}
 
+// DefaultApprovalTimeout is the maximum duration of a call to the approval handler if no timeout is specified
+const DefaultApprovalTimeout = 30 * time.Minute
+
+// Decisions that can be returned by the approval handler
+const (
+	ApprovalApprove = "approve"
+	ApprovalReject  = "reject"
+)
+
+// ApprovalRequest is sent as JSON to the approval handler (on the stdin of the program or as the body of a POST request)
+type ApprovalRequest struct {
+	RunID   string   `json:"run_id,omitempty"`
+	Module  string   `json:"module"`  // The folder of the module
+	Command []string `json:"command"` // The command that is waiting for the approval
+	Prompt  string   `json:"prompt"`  // The output of the command up to the approval prompt
+}
+
+// ApprovalDecision is the JSON response expected from the approval handler
+type ApprovalDecision struct {
+	Decision string `json:"decision"` // approve or reject
+	Reason   string `json:"reason,omitempty"`
+}
+
 // RunCommandToApprove runs a command with approval (expect style)
 func RunCommandToApprove(cmd *exec.Cmd, expectedStatements []string, completedStatements []string, terragruntOptions *options.TerragruntOptions) error {
 	sharedMutex.Lock()
@@ -30,6 +59,7 @@ func RunCommandToApprove(cmd *exec.Cmd, expectedStatements []string, completedSt
 	if err != nil {
 		return err
 	}
+	defer trackCommand(cmd)()
 	i := 0
 	for !stdOutInterceptor.WaitingForValue() && i < 30 {
 		time.Sleep(1 * time.Second)
@@ -43,14 +73,29 @@ func RunCommandToApprove(cmd *exec.Cmd, expectedStatements []string, completedSt
 		fmt.Println(stdOutInterceptor.GetBuffer())
 	}
 
+	var rejected error
 	if !stdOutInterceptor.IsComplete() {
 		var text string
-		if len(terragruntOptions.ApprovalHandler) > 0 {
-			text, err = approveWithCustomHandler(terragruntOptions, stdOutInterceptor.GetBuffer())
-		} else {
+		switch {
+		case terragruntOptions.ApprovalHandler == "":
 			text, err = approveInConsole()
+		case strings.Contains(terragruntOptions.ApprovalHandler, "{val}"):
+			terragruntOptions.Logger.Warning("The {val} placeholder of --terragrunt-approval is deprecated, the handler should read the JSON approval request instead")
+			text, err = approveWithCustomHandler(terragruntOptions, stdOutInterceptor.GetBuffer())
+		default:
+			var decision ApprovalDecision
+			request := newApprovalRequest(cmd, stdOutInterceptor.GetBuffer(), terragruntOptions)
+			if decision, err = RequestApproval(request, terragruntOptions); err == nil {
+				text = "yes\n"
+				if decision.Decision == ApprovalReject {
+					text, rejected = "no\n", tgerrors.WithStackTrace(errCommandRejected{request.Module, decision.Reason})
+				}
+			}
 		}
 		if err != nil {
+			// The command is no longer waiting for an answer once its input is closed
+			stdin.Close()
+			cmd.Wait()
 			return err
 		}
 		io.WriteString(stdin, text)
@@ -61,6 +106,9 @@ func RunCommandToApprove(cmd *exec.Cmd, expectedStatements []string, completedSt
 	}
 
 	err = cmd.Wait()
+	if rejected != nil {
+		return rejected
+	}
 	if err != nil {
 		return goErrors.New("terraform did not complete successfully")
 	}
@@ -68,6 +116,87 @@ func RunCommandToApprove(cmd *exec.Cmd, expectedStatements []string, completedSt
 	return nil
 }
 
+func newApprovalRequest(cmd *exec.Cmd, prompt string, terragruntOptions *options.TerragruntOptions) ApprovalRequest {
+	return ApprovalRequest{
+		RunID:   terragruntOptions.Env[options.EnvRunID],
+		Module:  filepath.Dir(terragruntOptions.TerragruntConfigPath),
+		Command: append([]string{filepath.Base(cmd.Args[0])}, cmd.Args[1:]...),
+		Prompt:  prompt,
+	}
+}
+
+// RequestApproval sends the request to the approval handler (--terragrunt-approval) and returns its decision. The
+// handler is either an URL (the request is posted) or a program (the request is written on its stdin), it must return
+// a JSON decision within the approval timeout. Every decision is logged.
+func RequestApproval(request ApprovalRequest, terragruntOptions *options.TerragruntOptions) (decision ApprovalDecision, err error) {
+	handler, timeout := terragruntOptions.ApprovalHandler, terragruntOptions.ApprovalTimeout
+	if timeout <= 0 {
+		timeout = DefaultApprovalTimeout
+	}
+
+	if strings.HasPrefix(handler, "http://") || strings.HasPrefix(handler, "https://") {
+		err = util.PostJSONWithResponse(handler, nil, request, timeout, &decision)
+		var timeoutErr interface{ Timeout() bool }
+		if goErrors.As(err, &timeoutErr) && timeoutErr.Timeout() {
+			err = tgerrors.WithStackTrace(errApprovalTimeout{handler, timeout})
+		}
+	} else {
+		decision, err = runApprovalProgram(handler, request, timeout, terragruntOptions)
+	}
+	if err == nil && decision.Decision != ApprovalApprove && decision.Decision != ApprovalReject {
+		err = tgerrors.WithStackTrace(errInvalidApprovalDecision{handler, decision.Decision})
+	}
+	if err != nil {
+		return decision, err
+	}
+
+	message := fmt.Sprintf("Approval handler decision for %s (%s): %s", util.GetPathRelativeToWorkingDir(request.Module), strings.Join(request.Command, " "), decision.Decision)
+	if decision.Reason != "" {
+		message += " (" + decision.Reason + ")"
+	}
+	terragruntOptions.Logger.Info(message)
+	return decision, nil
+}
+
+// Runs the approval program with the request on its stdin and reads the decision from its stdout
+func runApprovalProgram(handler string, request ApprovalRequest, timeout time.Duration, terragruntOptions *options.TerragruntOptions) (decision ApprovalDecision, err error) {
+	args := strings.Fields(handler)
+	command, err := LookPath(args[0], terragruntOptions.Env["PATH"])
+	if err != nil {
+		return decision, err
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return decision, tgerrors.WithStackTrace(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	approvalCmd := exec.CommandContext(ctx, command, args[1:]...)
+	approvalCmd.Stdin = bytes.NewReader(payload)
+	approvalCmd.Stderr = os.Stderr
+	output, err := approvalCmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return decision, tgerrors.WithStackTrace(errApprovalTimeout{handler, timeout})
+	}
+	if err != nil {
+		return decision, tgerrors.WithStackTrace(err)
+	}
+	if err = json.Unmarshal(output, &decision); err != nil {
+		// The handlers written for the previous versions print the answer to the prompt instead of a JSON decision
+		switch strings.ToLower(strings.TrimSpace(string(output))) {
+		case "y", "yes":
+			decision = ApprovalDecision{Decision: ApprovalApprove}
+		case "n", "no":
+			decision = ApprovalDecision{Decision: ApprovalReject}
+		default:
+			return decision, tgerrors.WithStackTrace(fmt.Errorf("invalid response from %s: %v", handler, err))
+		}
+		terragruntOptions.Logger.Warningf("The approval handler %s returned %q, it should return a JSON decision instead", handler, strings.TrimSpace(string(output)))
+	}
+	return decision, nil
+}
+
 func isDashAllQuery(terragruntArgs string) bool {
 	return strings.Contains(terragruntArgs, "-all")
 }
@@ -100,6 +229,7 @@ func approveWithCustomHandler(terragruntOptions *options.TerragruntOptions, prom
 // OutputInterceptor intercepts all writes to a io.Writer and writes them to a buffer while still letting them pass through.
 // Offers some functions to help the approval process.
 type OutputInterceptor struct {
+	mutex               sync.Mutex
 	subWriter           io.Writer
 	buffer              []byte
 	expectedStatements  []string
@@ -115,12 +245,17 @@ func newOutputInterceptor(subWriter io.Writer, expectedStatements []string, comp
 }
 
 func (interceptor *OutputInterceptor) Write(p []byte) (n int, err error) {
+	// The output is written by the command while the approval process reads the buffer
+	interceptor.mutex.Lock()
 	interceptor.buffer = append(interceptor.buffer, p...)
+	interceptor.mutex.Unlock()
 	return interceptor.subWriter.Write(p)
 }
 
 // GetBuffer returns the string value of all intercepted data to the underlying writer.
 func (interceptor *OutputInterceptor) GetBuffer() string {
+	interceptor.mutex.Lock()
+	defer interceptor.mutex.Unlock()
 	return string(interceptor.buffer)
 }
 
@@ -142,3 +277,35 @@ func (interceptor *OutputInterceptor) bufferContainsString(listOfStrings []strin
 	}
 	return false
 }
+
+// Custom error types
+
+type errCommandRejected struct {
+	Module string
+	Reason string
+}
+
+func (err errCommandRejected) Error() string {
+	if err.Reason == "" {
+		return fmt.Sprintf("The approval handler rejected the command of %s", util.GetPathRelativeToWorkingDir(err.Module))
+	}
+	return fmt.Sprintf("The approval handler rejected the command of %s: %s", util.GetPathRelativeToWorkingDir(err.Module), err.Reason)
+}
+
+type errApprovalTimeout struct {
+	Handler string
+	Timeout time.Duration
+}
+
+func (err errApprovalTimeout) Error() string {
+	return fmt.Sprintf("The approval handler %s did not answer within %v", err.Handler, err.Timeout)
+}
+
+type errInvalidApprovalDecision struct {
+	Handler  string
+	Decision string
+}
+
+func (err errInvalidApprovalDecision) Error() string {
+	return fmt.Sprintf("The approval handler %s returned an invalid decision %q (expected %s or %s)", err.Handler, err.Decision, ApprovalApprove, ApprovalReject)
+}
@@ -0,0 +1,61 @@
+package shell
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestApprovalHTTP(t *testing.T) {
+	t.Parallel()
+
+	request := ApprovalRequest{RunID: "run", Module: "/stack/vpc", Command: []string{"terraform", "apply"}, Prompt: "Plan: 1 to add\nEnter a value:"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var received ApprovalRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		assert.Equal(t, request, received)
+		switch r.URL.Path {
+		case "/approve":
+			json.NewEncoder(w).Encode(ApprovalDecision{Decision: ApprovalApprove})
+		case "/reject":
+			json.NewEncoder(w).Encode(ApprovalDecision{Decision: ApprovalReject, Reason: "change freeze"})
+		case "/invalid":
+			w.Write([]byte(`{"decision": "maybe"}`))
+		case "/slow":
+			time.Sleep(500 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path     string
+		expected ApprovalDecision
+		err      error
+	}{
+		{"/approve", ApprovalDecision{Decision: ApprovalApprove}, nil},
+		{"/reject", ApprovalDecision{Decision: ApprovalReject, Reason: "change freeze"}, nil},
+		{"/invalid", ApprovalDecision{Decision: "maybe"}, errInvalidApprovalDecision{server.URL + "/invalid", "maybe"}},
+		{"/slow", ApprovalDecision{}, errApprovalTimeout{server.URL + "/slow", 100 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			terragruntOptions := options.NewTerragruntOptionsForTest("/stack/vpc/terragrunt.hcl")
+			terragruntOptions.ApprovalHandler = server.URL + tt.path
+			terragruntOptions.ApprovalTimeout = 100 * time.Millisecond
+
+			decision, err := RequestApproval(request, terragruntOptions)
+			assert.Equal(t, tt.expected, decision)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.err, tgerrors.Unwrap(err))
+			}
+		})
+	}
+}
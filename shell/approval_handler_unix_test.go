@@ -0,0 +1,65 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package shell
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCommandToApproveWithProgram(t *testing.T) {
+	t.Parallel()
+
+	// The approval program saves the request it receives and returns the decision written in the decision file
+	folder := t.TempDir()
+	handler := filepath.Join(folder, "approve.sh")
+	script := "#!/bin/sh\ncat > " + folder + "/request.json\ncat " + folder + "/decision.json\n"
+	assert.NoError(t, os.WriteFile(handler, []byte(script), 0755))
+
+	tests := []struct {
+		name     string
+		decision ApprovalDecision
+		legacy   string // The plain answer returned by the handlers written for the previous versions
+		output   string
+		err      error
+	}{
+		{"approve", ApprovalDecision{Decision: ApprovalApprove}, "", "Enter a value:\nanswer: yes\n", nil},
+		{"reject", ApprovalDecision{Decision: ApprovalReject, Reason: "too many changes"}, "", "Enter a value:\nanswer: no\n", errCommandRejected{"/stack/vpc", "too many changes"}},
+		{"legacy approve", ApprovalDecision{}, "yes\n", "Enter a value:\nanswer: yes\n", nil},
+		{"legacy reject", ApprovalDecision{}, "no\n", "Enter a value:\nanswer: no\n", errCommandRejected{"/stack/vpc", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, _ := json.Marshal(tt.decision)
+			if tt.legacy != "" {
+				content = []byte(tt.legacy)
+			}
+			assert.NoError(t, os.WriteFile(filepath.Join(folder, "decision.json"), content, 0644))
+
+			terragruntOptions := options.NewTerragruntOptionsForTest("/stack/vpc/terragrunt.hcl")
+			terragruntOptions.ApprovalHandler = handler + " --verbose"
+			terragruntOptions.Env[options.EnvRunID] = "run"
+
+			var stdout bytes.Buffer
+			cmd := exec.Command("sh", "-c", `echo "Enter a value:"; read answer; echo "answer: $answer"`)
+			cmd.Stdout = &stdout
+			err := RunCommandToApprove(cmd, []string{"Enter a value:"}, []string{"Apply complete!"}, terragruntOptions)
+			assert.Equal(t, tt.err, tgerrors.Unwrap(err))
+			assert.Equal(t, tt.output, stdout.String())
+
+			var request ApprovalRequest
+			content, _ = os.ReadFile(filepath.Join(folder, "request.json"))
+			assert.NoError(t, json.Unmarshal(content, &request))
+			assert.Equal(t, ApprovalRequest{RunID: "run", Module: "/stack/vpc", Command: []string{"sh", "-c", `echo "Enter a value:"; read answer; echo "answer: $answer"`}, Prompt: "Enter a value:\n"}, request)
+		})
+	}
+}
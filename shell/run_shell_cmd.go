@@ -2,6 +2,8 @@ package shell
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -11,7 +13,9 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/coveooss/gotemplate/v3/collections"
 	"github.com/coveooss/gotemplate/v3/utils"
@@ -25,6 +29,7 @@ import (
 // CommandContext is the description of the command that must be executed
 type CommandContext struct {
 	Stdout, Stderr io.Writer // If these variables are left unset, the default value from current options set will be used
+	Stdin          io.Reader // If not specified, the standard input of terragrunt is forwarded to the command
 	DisplayCommand string    // If not specified, the actual command will be displayed
 	LogLevel       logrus.Level
 
@@ -38,6 +43,7 @@ type CommandContext struct {
 	env                 []string
 	workingDir          string
 	retries             int
+	timeout             time.Duration
 }
 
 // NewCmd initializes the ShellCommand object
@@ -91,6 +97,13 @@ func (c *CommandContext) WithRetries(retries int) *CommandContext {
 	return c
 }
 
+// WithTimeout sets the maximum duration of the command. Once the timeout is exceeded, the command receives a SIGTERM
+// and is killed if it is still running after a grace period.
+func (c *CommandContext) WithTimeout(timeout time.Duration) *CommandContext {
+	c.timeout = timeout
+	return c
+}
+
 // WorkingDir changes the default working directory for the command
 func (c *CommandContext) WorkingDir(wd string) *CommandContext {
 	c.workingDir = wd
@@ -163,6 +176,16 @@ func (c CommandContext) Run() error {
 			return tgerrors.WithStackTrace(err)
 		}
 
+		timeout := c.getTimeout()
+		if timeout < 0 {
+			return tgerrors.WithStackTrace(tgerrors.CommandTimeout{Command: c.DisplayCommand, Timeout: c.options.ModuleTimeout})
+		}
+		timedOut, stop := func() bool { return false }, func() {}
+		if timeout > 0 {
+			// The command must be created with its context before it is configured
+			cmd, timedOut, stop = newCommandWithTimeout(cmd.Path, cmd.Args, timeout, c.log)
+		}
+
 		verb := "Running "
 		if try > 0 {
 			verb = fmt.Sprintf("Trying(#%d)", try+1)
@@ -195,7 +218,20 @@ func (c CommandContext) Run() error {
 			finalStatus = RunCommandToApprove(cmd, c.expectedStatements, c.completedStatements, c.options)
 		} else {
 			cmd.Stdin = os.Stdin
-			finalStatus = cmd.Run()
+			if c.Stdin != nil {
+				cmd.Stdin = c.Stdin
+			}
+			if finalStatus = cmd.Start(); finalStatus == nil {
+				untrack := trackCommand(cmd)
+				finalStatus = cmd.Wait()
+				untrack()
+			}
+		}
+		stop()
+		if timedOut() {
+			// There is no retry if the command did not complete in time
+			cmdChannel <- finalStatus
+			return tgerrors.WithStackTrace(tgerrors.CommandTimeout{Command: c.DisplayCommand, Timeout: timeout})
 		}
 
 		cmdChannel <- finalStatus
@@ -209,6 +245,51 @@ func (c CommandContext) Run() error {
 	return tgerrors.WithStackTrace(finalStatus)
 }
 
+// Returns the timeout that should be applied to the command considering the command timeout and the module deadline
+// (0 means no timeout, a negative value means that the deadline has already been exceeded)
+func (c CommandContext) getTimeout() time.Duration {
+	timeout := c.timeout
+	if !c.options.Deadline.IsZero() {
+		remaining := time.Until(c.options.Deadline)
+		if remaining <= 0 {
+			return -1
+		}
+		if timeout == 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+	return timeout
+}
+
+// Delay between the SIGTERM and the SIGKILL sent to a command that exceeded its timeout
+var timeoutKillDelay = 10 * time.Second
+
+// Returns a new command (not configured yet) that is terminated (SIGTERM) if it is still running after the timeout and
+// killed if it does not exit within timeoutKillDelay after that. The termination is handled by os/exec, so it only
+// applies once the command has actually been started. Also returns a function indicating if the timeout has been
+// reached and a function that must be called once the command is completed.
+func newCommandWithTimeout(path string, args []string, timeout time.Duration, logger *multilogger.Logger) (*exec.Cmd, func() bool, func()) {
+	var timedOut atomic.Bool
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	timedCmd := exec.CommandContext(ctx, path)
+	timedCmd.Args = args
+	timedCmd.Cancel = func() error {
+		timedOut.Store(true)
+		logger.Warningf("Command %s did not complete within %v, terminating it", filepath.Base(timedCmd.Path), timeout)
+		if err := timedCmd.Process.Signal(syscall.SIGTERM); err != nil {
+			// The signal is not supported on all platforms
+			return timedCmd.Process.Kill()
+		}
+		return nil
+	}
+	timedCmd.WaitDelay = timeoutKillDelay
+	expired := func() bool {
+		// The command is not started at all if the timeout is reached before (only call it once the command is completed)
+		return timedOut.Load() || timedCmd.Process == nil && ctx.Err() == context.DeadlineExceeded
+	}
+	return timedCmd, expired, cancel
+}
+
 // LookPath search the supplied path to find the desired command
 // It uses a mutex since it has to temporary override the global PATH variable.
 func LookPath(command string, paths ...string) (string, error) {
@@ -255,7 +336,8 @@ func FilterPlanError(err error, command string) error {
 	return err
 }
 
-// SignalsForwarder forwards signals to a command, waiting for the command to finish.
+// SignalsForwarder forwards signals to a command, waiting for the command to finish. The first signal is forwarded
+// to let the command terminate gracefully, the command is killed if another signal is received.
 type SignalsForwarder chan os.Signal
 
 // NewSignalsForwarder returns a new SignalsForwarder
@@ -264,11 +346,24 @@ func NewSignalsForwarder(signals []os.Signal, c *exec.Cmd, logger *multilogger.L
 	signal.Notify(signalChannel, signals...)
 
 	go func() {
+		received := 0
 		for {
 			select {
-			case s := <-signalChannel:
-				logger.Warningf("Forward signal %v to terraform.", s)
-				err := c.Process.Signal(s)
+			case s, ok := <-signalChannel:
+				if !ok {
+					return
+				}
+				if s == nil || c.Process == nil {
+					continue
+				}
+				var err error
+				if received++; received == 1 {
+					logger.Warningf("Forward signal %v to terraform.", s)
+					err = c.Process.Signal(s)
+				} else {
+					logger.Warningf("Signal %v received again, killing %s.", s, filepath.Base(c.Path))
+					err = c.Process.Kill()
+				}
 				if err != nil {
 					logger.Errorf("Error forwarding signal: %v", err)
 				}
@@ -281,6 +376,37 @@ func NewSignalsForwarder(signals []os.Signal, c *exec.Cmd, logger *multilogger.L
 	return signalChannel
 }
 
+// The commands that are currently running (see KillRunningCommands)
+var runningCommands = struct {
+	sync.Mutex
+	commands map[*exec.Cmd]bool
+}{commands: map[*exec.Cmd]bool{}}
+
+// Register a started command as running, returns the function that must be called once the command is completed
+func trackCommand(cmd *exec.Cmd) func() {
+	runningCommands.Lock()
+	defer runningCommands.Unlock()
+	runningCommands.commands[cmd] = true
+	return func() {
+		runningCommands.Lock()
+		defer runningCommands.Unlock()
+		delete(runningCommands.commands, cmd)
+	}
+}
+
+// KillRunningCommands kills all the commands started by terragrunt that are still running (i.e. when the user
+// interrupts a stack execution for the second time)
+func KillRunningCommands(logger *multilogger.Logger) {
+	runningCommands.Lock()
+	defer runningCommands.Unlock()
+	for cmd := range runningCommands.commands {
+		logger.Warningf("Killing %s", filepath.Base(cmd.Path))
+		if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			logger.Errorf("Error killing %s: %v", filepath.Base(cmd.Path), err)
+		}
+	}
+}
+
 // Close closes the signal channel
 func (signalChannel *SignalsForwarder) Close() error {
 	signal.Stop(*signalChannel)
@@ -7,11 +7,14 @@ import (
 	"errors"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/coveooss/terragrunt/v2/options"
+	"github.com/coveooss/terragrunt/v2/tgerrors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -115,3 +118,67 @@ func TestNewSignalsForwarderMultipleUnix(t *testing.T) {
 	assert.True(t, retCode <= interrupts, "Subprocess received wrong number of signals")
 	assert.Equal(t, retCode, expectedInterrupts, "Subprocess didn't receive multiple signals")
 }
+
+func TestRunShellCommandTimeoutUnix(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("")
+	start := time.Now()
+	err := NewCmd(terragruntOptions, "sleep").Args("10").WithTimeout(500 * time.Millisecond).Run()
+	assert.Error(t, err)
+	assert.WithinDuration(t, start, time.Now(), 5*time.Second, "The command should have been stopped by the timeout")
+	retCode, err := GetExitCode(err)
+	assert.Nil(t, err)
+	assert.Equal(t, tgerrors.TimeoutExitCode, retCode)
+
+	// The timeout is applied even if it expires before the command is started
+	start = time.Now()
+	err = NewCmd(terragruntOptions, "sleep").Args("10").WithTimeout(time.Nanosecond).Run()
+	assert.WithinDuration(t, start, time.Now(), 5*time.Second, "The command should have been stopped by the timeout")
+	assert.IsType(t, tgerrors.CommandTimeout{}, tgerrors.Unwrap(err))
+
+	// The deadline is applied even if there is no timeout on the command itself
+	terragruntOptions.Deadline = time.Now().Add(-time.Second)
+	err = NewCmd(terragruntOptions, "sleep").Args("10").Run()
+	assert.IsType(t, tgerrors.CommandTimeout{}, tgerrors.Unwrap(err))
+}
+
+func TestRunShellCommandTimeoutConfigurationUnix(t *testing.T) {
+	t.Parallel()
+
+	// The command run with a timeout must keep the working directory, the environment and the input/output of the command
+	folder := t.TempDir()
+	terragruntOptions := options.NewTerragruntOptionsForTest(filepath.Join(folder, options.DefaultConfigName))
+	terragruntOptions.WorkingDir = folder
+	cmd := NewCmd(terragruntOptions, "sh").Args("-c", `pwd; echo "$TIMEOUT_TEST"; cat`).Env("TIMEOUT_TEST=value").WithTimeout(time.Minute)
+	cmd.Stdin = strings.NewReader("input")
+	out, err := cmd.Output()
+	assert.NoError(t, err)
+	expectedFolder, _ := filepath.EvalSymlinks(folder)
+	assert.Equal(t, expectedFolder+"\nvalue\ninput", out)
+}
+
+func TestKillRunningCommandsUnix(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("")
+	result := make(chan error)
+	start := time.Now()
+	go func() { result <- NewCmd(terragruntOptions, "sleep").Args("10").Run() }()
+
+	isRunning := func() bool {
+		runningCommands.Lock()
+		defer runningCommands.Unlock()
+		for cmd := range runningCommands.commands {
+			if filepath.Base(cmd.Path) == "sleep" {
+				return true
+			}
+		}
+		return false
+	}
+	assert.Eventually(t, isRunning, 5*time.Second, 10*time.Millisecond)
+	KillRunningCommands(terragruntOptions.Logger)
+	assert.Error(t, <-result)
+	assert.WithinDuration(t, start, time.Now(), 5*time.Second, "The command should have been killed")
+	assert.False(t, isRunning())
+}
@@ -2,6 +2,7 @@ package tgerrors
 
 import (
 	"fmt"
+	"time"
 
 	goerrors "github.com/go-errors/errors"
 )
@@ -12,6 +13,10 @@ type Error = goerrors.Error
 // ChangeExitCode is the exit code returned on terraform plan with changes
 const ChangeExitCode = 2
 
+// TimeoutExitCode is the exit code returned when a command has been stopped because it exceeded its timeout
+// (same as the timeout command)
+const TimeoutExitCode = 124
+
 // WithStackTrace wraps the given error in an Error type that contains the stack trace. If the given error already has a stack trace,
 // it is used directly. If the given error is nil, return nil.
 func WithStackTrace(err error) error {
@@ -101,3 +106,18 @@ func (err PlanWithChanges) Error() string {
 func (err PlanWithChanges) ExitStatus() (int, error) {
 	return ChangeExitCode, nil
 }
+
+// CommandTimeout represents the situation where a command has been stopped because it did not complete in time
+type CommandTimeout struct {
+	Command string
+	Timeout time.Duration
+}
+
+func (err CommandTimeout) Error() string {
+	return fmt.Sprintf("Command %s has been stopped because it did not complete within %v", err.Command, err.Timeout)
+}
+
+// ExitStatus returns the exit code associated with this error
+func (err CommandTimeout) ExitStatus() (int, error) {
+	return TimeoutExitCode, nil
+}
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+)
+
+// ExtractJSONOutput returns the JSON document printed by a command such as terraform output -json. The output may also
+// contain lines written by the hooks, so we decode the first JSON object that starts at the beginning of a line.
+func ExtractJSONOutput(output string) (json.RawMessage, error) {
+	offset := 0
+	for _, line := range strings.SplitAfter(output, "\n") {
+		if strings.HasPrefix(line, "{") {
+			var value json.RawMessage
+			err := json.NewDecoder(strings.NewReader(output[offset:])).Decode(&value)
+			return value, err
+		}
+		offset += len(line)
+	}
+	return nil, fmt.Errorf("no JSON object found in the output")
+}
+
+// ParseTerraformOutputs returns the value of each output from the output of terraform output -json
+func ParseTerraformOutputs(output string) (map[string]interface{}, error) {
+	content, err := ExtractJSONOutput(output)
+	if err != nil {
+		return nil, tgerrors.WithStackTrace(err)
+	}
+	var outputs map[string]struct {
+		Value interface{} `json:"value"`
+	}
+	if err := json.Unmarshal(content, &outputs); err != nil {
+		return nil, tgerrors.WithStackTrace(err)
+	}
+	result := make(map[string]interface{}, len(outputs))
+	for name, output := range outputs {
+		result[name] = output.Value
+	}
+	return result, nil
+}
+
+// ParseTerraformState returns the serial and the outputs of a state (as returned by terraform state pull). The outputs
+// are returned in the same format as terraform output -json.
+func ParseTerraformState(state string) (serial int64, outputs json.RawMessage, err error) {
+	content, err := ExtractJSONOutput(state)
+	if err != nil {
+		return 0, nil, tgerrors.WithStackTrace(err)
+	}
+	type output struct {
+		Sensitive bool            `json:"sensitive"`
+		Type      json.RawMessage `json:"type"`
+		Value     json.RawMessage `json:"value"`
+	}
+	var decoded struct {
+		Serial  int64             `json:"serial"`
+		Outputs map[string]output `json:"outputs"`
+	}
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		return 0, nil, tgerrors.WithStackTrace(err)
+	}
+	if decoded.Outputs == nil {
+		decoded.Outputs = map[string]output{}
+	}
+	if outputs, err = json.Marshal(decoded.Outputs); err != nil {
+		return 0, nil, tgerrors.WithStackTrace(err)
+	}
+	return decoded.Serial, outputs, nil
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractJSONOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+		wantErr  bool
+	}{
+		{"Only JSON", "{\n  \"a\": 1\n}\n", "{\n  \"a\": 1\n}", false},
+		{"With hook output", "Running hook {not json}\n{\"a\": {\"value\": 1}}\nDone\n", `{"a": {"value": 1}}`, false},
+		{"Empty", "{}\n", "{}", false},
+		{"No JSON", "Nothing to output\n", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ExtractJSONOutput(tt.output)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(actual))
+		})
+	}
+}
+
+func TestParseTerraformOutputs(t *testing.T) {
+	t.Parallel()
+
+	outputs, err := ParseTerraformOutputs(`{"id": {"sensitive": false, "type": "string", "value": "vpc-1"}, "ports": {"value": [80, 443]}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": "vpc-1", "ports": []interface{}{80.0, 443.0}}, outputs)
+}
+
+func TestParseTerraformState(t *testing.T) {
+	t.Parallel()
+
+	serial, outputs, err := ParseTerraformState(`{"version": 4, "serial": 12, "outputs": {"id": {"value": "vpc-1", "type": "string"}, "password": {"value": "secret", "type": "string", "sensitive": true}}, "resources": []}`)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), serial)
+	assert.JSONEq(t, `{"id": {"sensitive": false, "type": "string", "value": "vpc-1"}, "password": {"sensitive": true, "type": "string", "value": "secret"}}`, string(outputs))
+
+	serial, outputs, err = ParseTerraformState(`{"version": 4, "serial": 1}`)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), serial)
+	assert.Equal(t, "{}", string(outputs))
+}
@@ -0,0 +1,256 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+)
+
+// MaxTimingHistory is the number of executions kept for each module, command and hook in the timing history
+const MaxTimingHistory = 10
+
+// MaxTimingHistoryModules is the maximum number of modules kept in the timing history (the most recently executed)
+const MaxTimingHistoryModules = 1000
+
+// TimingHistoryRetention is the delay after which the timings of a module that has not been executed are removed
+const TimingHistoryRetention = 90 * 24 * time.Hour
+
+// TimingHistory contains the durations of the last executions of the modules, indexed by module path and command
+type TimingHistory map[string]map[string]*Timings
+
+// Timings contains the durations of the last executions of a module (and its hooks) for a specific command
+type Timings struct {
+	Durations Durations            `json:"durations,omitempty"`
+	Hooks     map[string]Durations `json:"hooks,omitempty"`
+	Updated   time.Time            `json:"updated"`
+}
+
+// Durations represents the durations of the successive executions of a module or a hook
+type Durations []time.Duration
+
+// Last returns the duration of the last execution (0 if there is none)
+func (durations Durations) Last() time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	return durations[len(durations)-1]
+}
+
+// Average returns the average duration of the executions (0 if there is none)
+func (durations Durations) Average() time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+	return total / time.Duration(len(durations))
+}
+
+// Get returns the timings of the module for the command (nil if there is none)
+func (history TimingHistory) Get(modulePath, command string) *Timings {
+	return history[modulePath][command]
+}
+
+// GetTimingHistoryFile returns the file used to save the timing history (in the terragrunt cache folder)
+func GetTimingHistoryFile() string {
+	return GetTempDownloadFolder("terragrunt-timings.json")
+}
+
+// LoadTimingHistory reads the timing history file (returns an empty history if the file does not exist)
+func LoadTimingHistory() (TimingHistory, error) {
+	history := TimingHistory{}
+	content, err := os.ReadFile(GetTimingHistoryFile())
+	if os.IsNotExist(err) {
+		return history, nil
+	} else if err != nil {
+		return history, tgerrors.WithStackTrace(err)
+	}
+	if err := json.Unmarshal(content, &history); err != nil {
+		return TimingHistory{}, tgerrors.WithStackTrace(err)
+	}
+	return history, nil
+}
+
+// The timings recorded by the current process, they are merged into the timing history file by SaveTimingHistory
+var pendingTimings = struct {
+	sync.Mutex
+	history TimingHistory
+}{history: TimingHistory{}}
+
+// RecordModuleTiming adds the duration of the module execution to the timings that will be saved at the end of the run
+func RecordModuleTiming(modulePath, command string, duration time.Duration) {
+	recordTiming(modulePath, command, func(timings *Timings) {
+		timings.Durations = appendTiming(timings.Durations, duration)
+	})
+}
+
+// RecordHookTiming adds the duration of the hook execution to the timings that will be saved at the end of the run
+func RecordHookTiming(modulePath, command, hook string, duration time.Duration) {
+	recordTiming(modulePath, command, func(timings *Timings) {
+		if timings.Hooks == nil {
+			timings.Hooks = map[string]Durations{}
+		}
+		timings.Hooks[hook] = appendTiming(timings.Hooks[hook], duration)
+	})
+}
+
+func recordTiming(modulePath, command string, update func(*Timings)) {
+	pendingTimings.Lock()
+	defer pendingTimings.Unlock()
+	update(pendingTimings.history.getOrCreate(modulePath, command))
+}
+
+// Returns the timings of the module for the command, creating them if they do not exist
+func (history TimingHistory) getOrCreate(modulePath, command string) *Timings {
+	if history[modulePath] == nil {
+		history[modulePath] = map[string]*Timings{}
+	}
+	if history[modulePath][command] == nil {
+		history[modulePath][command] = &Timings{}
+	}
+	return history[modulePath][command]
+}
+
+// Only keep the last executions
+func appendTiming(durations Durations, duration time.Duration) Durations {
+	durations = append(durations, duration)
+	if len(durations) > MaxTimingHistory {
+		durations = durations[len(durations)-MaxTimingHistory:]
+	}
+	return durations
+}
+
+// Add the timings recorded by the current process to the history
+func (history TimingHistory) merge(recorded TimingHistory, now time.Time) {
+	for modulePath, commands := range recorded {
+		for command, recordedTimings := range commands {
+			timings := history.getOrCreate(modulePath, command)
+			for _, duration := range recordedTimings.Durations {
+				timings.Durations = appendTiming(timings.Durations, duration)
+			}
+			for hook, durations := range recordedTimings.Hooks {
+				if timings.Hooks == nil {
+					timings.Hooks = map[string]Durations{}
+				}
+				for _, duration := range durations {
+					timings.Hooks[hook] = appendTiming(timings.Hooks[hook], duration)
+				}
+			}
+			timings.Updated = now
+		}
+	}
+}
+
+// Remove the modules that have not been executed since the retention delay and only keep the most recently executed
+// modules if there are more than MaxTimingHistoryModules
+func (history TimingHistory) prune(now time.Time) {
+	lastUpdates := make(map[string]time.Time, len(history))
+	for modulePath, commands := range history {
+		for _, timings := range commands {
+			if timings.Updated.After(lastUpdates[modulePath]) {
+				lastUpdates[modulePath] = timings.Updated
+			}
+		}
+		if now.Sub(lastUpdates[modulePath]) > TimingHistoryRetention {
+			delete(history, modulePath)
+			delete(lastUpdates, modulePath)
+		}
+	}
+
+	if len(history) > MaxTimingHistoryModules {
+		modules := make([]string, 0, len(history))
+		for modulePath := range history {
+			modules = append(modules, modulePath)
+		}
+		sort.Slice(modules, func(i, j int) bool { return lastUpdates[modules[i]].After(lastUpdates[modules[j]]) })
+		for _, modulePath := range modules[MaxTimingHistoryModules:] {
+			delete(history, modulePath)
+		}
+	}
+}
+
+// SaveTimingHistory merges the timings recorded by the current process into the timing history file. The file is
+// locked during the update to avoid losing the timings saved by concurrent processes and it is replaced atomically.
+func SaveTimingHistory() error {
+	pendingTimings.Lock()
+	defer pendingTimings.Unlock()
+	if len(pendingTimings.history) == 0 {
+		return nil
+	}
+
+	filename := GetTimingHistoryFile()
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	unlock, err := lockFile(filename+".lock", timingHistoryLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	history, err := LoadTimingHistory()
+	if err != nil {
+		// If the history is corrupted, we start a new one
+		history = TimingHistory{}
+	}
+	now := time.Now()
+	history.merge(pendingTimings.history, now)
+	history.prune(now)
+
+	content, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.Write(content); err != nil {
+		tempFile.Close()
+		return tgerrors.WithStackTrace(err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	if err := os.Rename(tempFile.Name(), filename); err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	pendingTimings.history = TimingHistory{}
+	return nil
+}
+
+// Maximum delay to acquire the lock of the timing history file, a lock older than that is considered as abandoned
+const timingHistoryLockTimeout = 10 * time.Second
+
+// Create the lock file (it must not exist), waiting for the other processes to release it. Returns the function that
+// releases the lock.
+func lockFile(filename string, timeout time.Duration) (func(), error) {
+	for start := time.Now(); ; {
+		file, err := os.OpenFile(filename, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(filename) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, tgerrors.WithStackTrace(err)
+		}
+		if info, statErr := os.Stat(filename); statErr == nil && time.Since(info.ModTime()) > timeout {
+			// The process holding the lock has most likely been killed
+			os.Remove(filename)
+			continue
+		}
+		if time.Since(start) > timeout {
+			return nil, tgerrors.WithStackTrace(fmt.Errorf("timeout while waiting for the lock %s", filename))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,109 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimingHistory(t *testing.T) {
+	t.Setenv("TERRAGRUNT_CACHE", t.TempDir())
+
+	history, err := LoadTimingHistory()
+	assert.NoError(t, err)
+	assert.Nil(t, history.Get("/stack/network", "apply"))
+
+	for i := 1; i <= MaxTimingHistory+2; i++ {
+		RecordModuleTiming("/stack/network", "apply", time.Duration(i)*time.Second)
+		if i == 5 {
+			// The timings are merged with the ones already saved
+			assert.NoError(t, SaveTimingHistory())
+		}
+	}
+	RecordModuleTiming("/stack/network", "plan", 5*time.Second)
+	RecordHookTiming("/stack/network", "apply", "pre_hook lint", 2*time.Second)
+	RecordHookTiming("/stack/network", "apply", "pre_hook lint", 4*time.Second)
+
+	history, err = LoadTimingHistory()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(history.Get("/stack/network", "apply").Durations), "Only saved at the end of the run")
+	assert.NoError(t, SaveTimingHistory())
+
+	history, err = LoadTimingHistory()
+	assert.NoError(t, err)
+	apply := history.Get("/stack/network", "apply")
+	if assert.NotNil(t, apply) {
+		// Only the last executions are kept
+		assert.Equal(t, MaxTimingHistory, len(apply.Durations))
+		assert.Equal(t, 12*time.Second, apply.Durations.Last())
+		assert.Equal(t, 7500*time.Millisecond, apply.Durations.Average())
+		assert.Equal(t, Durations{2 * time.Second, 4 * time.Second}, apply.Hooks["pre_hook lint"])
+		assert.Equal(t, 3*time.Second, apply.Hooks["pre_hook lint"].Average())
+	}
+	assert.Equal(t, Durations{5 * time.Second}, history.Get("/stack/network", "plan").Durations)
+	assert.Equal(t, time.Duration(0), Durations{}.Average())
+	assert.Equal(t, time.Duration(0), Durations{}.Last())
+}
+
+func TestTimingHistoryPrune(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	history := TimingHistory{
+		"/stack/old":    {"apply": {Durations: Durations{time.Second}, Updated: now.Add(-TimingHistoryRetention - time.Hour)}},
+		"/stack/recent": {"apply": {Durations: Durations{time.Second}, Updated: now.Add(-TimingHistoryRetention - time.Hour)}, "plan": {Updated: now}},
+	}
+	for i := 0; i < MaxTimingHistoryModules; i++ {
+		history[fmt.Sprintf("/stack/module-%d", i)] = map[string]*Timings{"apply": {Updated: now.Add(-time.Duration(i+1) * time.Minute)}}
+	}
+	history.prune(now)
+
+	assert.Equal(t, MaxTimingHistoryModules, len(history))
+	assert.Nil(t, history["/stack/old"], "Not executed since the retention delay")
+	assert.NotNil(t, history.Get("/stack/recent", "apply"), "Another command has been executed recently")
+	assert.NotNil(t, history["/stack/module-0"])
+	assert.Nil(t, history[fmt.Sprintf("/stack/module-%d", MaxTimingHistoryModules-1)], "Least recently executed module")
+}
+
+func TestTimingHistoryLock(t *testing.T) {
+	t.Parallel()
+
+	filename := fmt.Sprintf("%s/test.lock", t.TempDir())
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var active, maxActive int
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := lockFile(filename, time.Second)
+			if !assert.NoError(t, err) {
+				return
+			}
+			mutex.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			mutex.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mutex.Lock()
+			active--
+			mutex.Unlock()
+			unlock()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, maxActive)
+
+	// An abandoned lock is removed after the timeout
+	assert.NoError(t, os.WriteFile(filename, nil, 0644))
+	assert.NoError(t, os.Chtimes(filename, time.Now().Add(-time.Minute), time.Now().Add(-time.Minute)))
+	unlock, err := lockFile(filename, time.Second)
+	assert.NoError(t, err)
+	unlock()
+}
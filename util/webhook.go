@@ -0,0 +1,66 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+)
+
+// DefaultWebhookTimeout is the maximum duration of a webhook call if no timeout is specified
+const DefaultWebhookTimeout = 30 * time.Second
+
+// PostJSON sends the payload encoded as JSON to the url and returns an error if the response status is not 2xx
+func PostJSON(url string, headers map[string]string, payload interface{}, timeout time.Duration) error {
+	return PostJSONWithResponse(url, headers, payload, timeout, nil)
+}
+
+// PostJSONWithResponse sends the payload encoded as JSON to the url and decodes the JSON response into result (if not nil)
+func PostJSONWithResponse(url string, headers map[string]string, payload interface{}, timeout time.Duration, result interface{}) error {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(content))
+	if err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	response, err := (&http.Client{Timeout: timeout}).Do(request)
+	if err != nil {
+		return tgerrors.WithStackTrace(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return tgerrors.WithStackTrace(WebhookError{url, response.Status, string(body)})
+	}
+	if result != nil {
+		if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+			return tgerrors.WithStackTrace(fmt.Errorf("invalid response from %s: %v", url, err))
+		}
+	}
+	return nil
+}
+
+// WebhookError indicates that the webhook returned an unexpected status
+type WebhookError struct {
+	URL    string
+	Status string
+	Body   string
+}
+
+func (err WebhookError) Error() string {
+	return fmt.Sprintf("Webhook %s returned %s: %s", err.URL, err.Status, err.Body)
+}
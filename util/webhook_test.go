@@ -0,0 +1,27 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coveooss/terragrunt/v2/tgerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostJSON(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		if r.URL.Path == "/fail" {
+			http.Error(w, "bad payload", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	assert.NoError(t, PostJSON(server.URL, nil, map[string]string{"type": "test"}, 0))
+
+	err := PostJSON(server.URL+"/fail", nil, map[string]string{"type": "test"}, 0)
+	assert.Equal(t, WebhookError{server.URL + "/fail", "400 Bad Request", "bad payload\n"}, tgerrors.Unwrap(err))
+}